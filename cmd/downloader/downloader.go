@@ -55,16 +55,32 @@ func entrypoint(cmd *cobra.Command, outputDir string) error {
 		return fmt.Errorf("output directory %q is not empty", outputDir)
 	}
 
-	return downloader.Download(cmd.Context(), version, sha256sum, outputDir)
+	progress, err := newProgressReporter(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, err = downloader.Download(cmd.Context(), version, sha256sum, outputDir, progress)
+	return err
 }
 
 // main runs sets up and runs Cobra.
 func main() {
-	rootCmd.PersistentFlags().String("version", "stable",
-		"The version of Factorio to download. Can be 'stable' or 'experimental' for latest release in that channel.")
-	rootCmd.PersistentFlags().String("sha256sum", "",
+	rootCmd.Flags().String("version", "stable",
+		"The version of Factorio to download. Can be 'stable' or 'experimental' for latest release in that channel, "+
+			"or a constraint (e.g., '~2.0') for the newest release satisfying it.")
+	rootCmd.Flags().String("sha256sum", "",
 		"The SHA256 sum of the Factorio download, if set it will be used instead of fetching it.")
 
+	rootCmd.PersistentFlags().String("progress", "auto",
+		"How to report download progress. Can be 'bar', 'log', 'none' or 'auto' to use a bar when attached to a terminal.")
+
+	verifyCmd.Flags().String("version", "stable",
+		"The version of Factorio to verify against. Can be 'stable' or 'experimental' for latest release in that channel, "+
+			"or a constraint (e.g., '~2.0') for the newest release satisfying it.")
+
+	rootCmd.AddCommand(listCmd, latestCmd, verifyCmd)
+
 	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
 		os.Exit(1)
 	}
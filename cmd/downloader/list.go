@@ -0,0 +1,83 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/spf13/cobra"
+)
+
+// listCmd lists all published headless versions of Factorio.
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all published headless Factorio versions",
+	Long: "Lists all published headless Factorio versions. Factorio only " +
+		"publishes which versions are currently the latest stable and " +
+		"experimental releases, not which channel older versions were " +
+		"released in, so only the current latest releases are marked with " +
+		"their channel.",
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		rels, err := downloader.ListReleases()
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tLATEST\tSHA256")
+		for i := range rels {
+			latest := make([]string, len(rels[i].Latest))
+			for j, c := range rels[i].Latest {
+				latest[j] = c.String()
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", rels[i].Version, strings.Join(latest, ","), rels[i].SHA256)
+		}
+		return tw.Flush()
+	},
+}
+
+// latestCmd prints the latest version of each channel.
+var latestCmd = &cobra.Command{
+	Use:   "latest",
+	Short: "Prints the latest stable and experimental headless Factorio versions",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CHANNEL\tVERSION\tSHA256")
+		for _, channel := range []downloader.Channel{downloader.ChannelStable, downloader.ChannelExperimental} {
+			version, err := downloader.GetVersionForChannel(channel)
+			if err != nil {
+				return err
+			}
+
+			sha256sum, err := factorio.GetSHA256(version)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", channel, version, sha256sum)
+		}
+		return tw.Flush()
+	},
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	charmlog "github.com/charmbracelet/log"
+	"github.com/dustin/go-humanize"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// barWidth is the width, in characters, of the progress bar.
+const barWidth = 30
+
+// barProgressReporter renders download progress as a progress bar on a
+// terminal.
+type barProgressReporter struct {
+	w io.Writer
+}
+
+// Report implements factorio.ProgressReporter.
+func (r *barProgressReporter) Report(p *factorio.Progress) {
+	//nolint:gosec // Why: These are >0.
+	status := fmt.Sprintf("%s/s", humanize.Bytes(uint64(p.Rate())))
+	//nolint:gosec // Why: This is a >0.
+	downloaded := humanize.Bytes(uint64(p.Downloaded))
+
+	percent := p.Percent()
+	if percent < 0 {
+		// We don't know the size, so we can only show how much we've
+		// downloaded.
+		fmt.Fprintf(r.w, "\r%s  %s ", downloaded, status)
+	} else {
+		filled := int(percent / 100 * barWidth)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+		if eta := p.ETA(); eta > 0 {
+			status += fmt.Sprintf("  ETA %s", eta)
+		}
+
+		//nolint:gosec // Why: This is a >0.
+		fmt.Fprintf(r.w, "\r[%s] %3d%%  %s / %s  %s ", bar, int(percent), downloaded,
+			humanize.Bytes(uint64(p.Total)), status)
+	}
+
+	if p.Done {
+		fmt.Fprintf(r.w, "\nDownloaded and validated Factorio version %s in %s\n",
+			p.Version, p.Elapsed.Round(time.Second))
+	}
+}
+
+// newProgressReporter returns a progress reporter based on the
+// --progress flag.
+func newProgressReporter(cmd *cobra.Command) (factorio.ProgressReporter, error) {
+	mode, _ := cmd.Flags().GetString("progress") //nolint:errcheck // Why: Defaults.
+	if mode == "auto" {
+		mode = "log"
+		if isatty.IsTerminal(os.Stderr.Fd()) {
+			mode = "bar"
+		}
+	}
+
+	switch mode {
+	case "bar":
+		return &barProgressReporter{w: os.Stderr}, nil
+	case "log":
+		return factorio.NewLogProgressReporter(slog.New(charmlog.New(os.Stderr)), 5*time.Second), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown progress mode %q, expected auto, bar, log or none", mode)
+	}
+}
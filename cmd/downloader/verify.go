@@ -0,0 +1,81 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd re-hashes an installed Factorio server.
+var verifyCmd = &cobra.Command{
+	Use:   "verify <dir>",
+	Short: "Verifies an installed Factorio server against the official release",
+	Long: "Verifies an installed Factorio server against the official release. " +
+		"If --version is not provided and the wrapper recorded a manifest in the " +
+		"directory at install time, the install is verified against it without " +
+		"downloading anything.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+
+		version, _ := cmd.Flags().GetString("version") //nolint:errcheck // Why: Defaults.
+
+		progress, err := newProgressReporter(cmd)
+		if err != nil {
+			return err
+		}
+
+		var problems []factorio.ManifestProblem
+		st, err := state.Open(filepath.Join(dir, "state.json"))
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("version") && st.Version != "" {
+			if len(st.Manifest) > 0 {
+				fmt.Println("Verifying", dir, "against recorded manifest for Factorio version", st.Version)
+				problems, err = st.Manifest.Verify(dir, factorio.VerifyFull)
+			} else {
+				fmt.Println("Verifying", dir, "against Factorio version", st.Version)
+				problems, err = downloader.Verify(cmd.Context(), st.Version, dir, progress)
+			}
+		} else {
+			fmt.Println("Verifying", dir, "against Factorio version", version)
+			problems, err = downloader.Verify(cmd.Context(), version, dir, progress)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			fmt.Println(" ->", p)
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d file(s) failed verification", len(problems))
+		}
+
+		fmt.Println("All files verified")
+		return nil
+	},
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configCmd is the parent command for inspecting the configuration.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the wrapper's configuration",
+}
+
+// configPrintCmd prints the effective configuration.
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Prints the effective configuration, with secrets redacted",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		b, err := cfg.MarshalRedacted()
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(b)
+		return err
+	},
+}
+
+// configValidateCmd validates the configuration.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the configuration, reporting every problem found",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		fmt.Println("Configuration is valid")
+		return nil
+	},
+}
+
+// configDocsCmd prints the configuration reference.
+var configDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Prints a reference of every supported configuration value",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		format, _ := cmd.Flags().GetString("format") //nolint:errcheck // Why: Defaults.
+
+		var b []byte
+		switch format {
+		case "markdown":
+			b = config.Markdown(config.Docs())
+		case "json-schema":
+			var err error
+			b, err = config.JSONSchema(config.Docs())
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown format %q, expected markdown or json-schema", format)
+		}
+
+		_, err := os.Stdout.Write(b)
+		return err
+	},
+}
+
+func init() {
+	configDocsCmd.Flags().String("format", "markdown", "The format to print the reference in, 'markdown' or 'json-schema'.")
+
+	configCmd.AddCommand(configPrintCmd, configValidateCmd, configDocsCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"io"
+	"log/slog"
+
+	charmlog "github.com/charmbracelet/log"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// newLogger returns a logger writing to w in the format and at the
+// level configured in cfg. cfg must have been validated.
+func newLogger(w io.Writer, cfg *config.Config) *slog.Logger {
+	level, err := cfg.SlogLevel()
+	if err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	switch cfg.LogFormat {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts))
+	case "logfmt":
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+
+	return slog.New(charmlog.NewWithOptions(w, charmlog.Options{Level: charmlog.Level(level)}))
+}
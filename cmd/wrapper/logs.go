@@ -0,0 +1,74 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/serverlog"
+)
+
+// logsCmd prints the stored output of the server.
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Prints the stored output of the Factorio server, including rotated logs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		since, _ := cmd.Flags().GetDuration("since") //nolint:errcheck // Why: Defaults.
+		pattern, _ := cmd.Flags().GetString("grep")  //nolint:errcheck // Why: Defaults.
+
+		var match func(string) bool
+		if pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid --grep: %w", err)
+			}
+			match = re.MatchString
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		var sinceTime time.Time
+		if since > 0 {
+			sinceTime = time.Now().Add(-since)
+		}
+
+		w := bufio.NewWriter(os.Stdout)
+		if err := serverlog.Read(serverlog.Dir(cfg.ServerDataPath), sinceTime, match, w); err != nil {
+			return err
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	logsCmd.Flags().Duration("since", 0, "Only print lines written within this duration, e.g., '1h'.")
+	logsCmd.Flags().String("grep", "", "Only print lines matching this regular expression.")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/discord"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+)
+
+// reloader applies configuration changes to a running server, and to
+// the notifier and Discord bridge, which may be nil.
+type reloader struct {
+	log    *slog.Logger
+	n      *notify.Notifier
+	bridge *discord.Bridge
+
+	mu  sync.Mutex
+	cfg *config.Config
+	srv *launcher.Server
+
+	// applyMu is held while player lists are applied, so that concurrent
+	// applies don't interleave their commands.
+	applyMu sync.Mutex
+}
+
+// newReloader returns a reloader for the provided configuration.
+func newReloader(log *slog.Logger, cfg *config.Config, n *notify.Notifier, bridge *discord.Bridge) *reloader {
+	return &reloader{log: log, n: n, bridge: bridge, cfg: cfg}
+}
+
+// config returns a copy of the current configuration, which includes
+// any changes made by reloading it.
+func (r *reloader) config() *config.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg := *r.cfg
+	return &cfg
+}
+
+// setServer records the running server, applying the current
+// configuration to it.
+func (r *reloader) setServer(ctx context.Context, srv *launcher.Server) {
+	r.mu.Lock()
+	r.srv = srv
+	r.mu.Unlock()
+
+	r.applyPlayers(ctx)
+}
+
+// applyPlayers applies the desired player lists to the running server,
+// if there is one, returning true if they were applied. Player lists are
+// fetched without holding r.mu, so that a slow sync source doesn't block
+// reloads.
+func (r *reloader) applyPlayers(ctx context.Context) bool {
+	r.mu.Lock()
+	cfg, srv := r.cfg, r.srv
+	r.mu.Unlock()
+
+	if srv == nil {
+		return false
+	}
+
+	desired, err := launcher.DesiredPlayers(ctx, cfg)
+	if err != nil {
+		r.log.Error("Failed to sync player lists", "err", err)
+		return false
+	}
+
+	r.applyMu.Lock()
+	defer r.applyMu.Unlock()
+	if err := srv.ApplyPlayers(ctx, r.log, cfg, desired); err != nil {
+		r.log.Error("Failed to apply player lists", "err", err)
+		return false
+	}
+	return true
+}
+
+// reload loads the configuration again and applies it.
+func (r *reloader) reload(ctx context.Context) {
+	if !r.reloadConfig() {
+		return
+	}
+
+	if r.applyPlayers(ctx) {
+		r.log.Info("Applied player lists")
+	}
+}
+
+// reloadConfig loads the configuration again, returning true if it
+// was valid and replaced the current one.
+func (r *reloader) reloadConfig() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newCfg, err := r.cfg.Reload()
+	if err != nil {
+		r.log.Error("Failed to reload configuration", "err", err)
+		return false
+	}
+	if err := newCfg.Validate(); err != nil {
+		r.log.Error("Invalid configuration, ignoring reload", "err", err)
+		return false
+	}
+	r.cfg = newCfg
+
+	r.n.SetSecret(newCfg.Webhooks.Secret)
+	if r.bridge != nil {
+		r.bridge.SetToken(newCfg.Discord.Token)
+	}
+
+	if err := launcher.UpdateServerSettings(newCfg); err != nil {
+		r.log.Error("Failed to update server settings", "err", err)
+	} else {
+		r.log.Info("Reloaded secrets, server settings will be used on the next restart")
+	}
+	return true
+}
+
+// syncInterval returns how often player lists should be synced, or zero
+// if there is no sync source.
+func (r *reloader) syncInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cfg.Players.SyncSource == "" {
+		return 0
+	}
+	return r.cfg.Players.SyncInterval
+}
+
+// syncPlayers periodically applies the player lists from the configured
+// sync source to the running server, until the context is canceled. The
+// sync source and interval are read again after every sync, so they can
+// be changed by reloading the configuration.
+func (r *reloader) syncPlayers(ctx context.Context) {
+	// How often to check if syncing has been enabled.
+	const disabledInterval = time.Minute
+
+	for {
+		wait := r.syncInterval()
+		enabled := wait > 0
+		if !enabled {
+			wait = disabledInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if !enabled {
+			continue
+		}
+
+		r.applyPlayers(ctx)
+	}
+}
+
+// run reloads the configuration whenever the wrapper receives a SIGHUP,
+// until the context is canceled.
+func (r *reloader) run(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+		}
+
+		r.log.Info("Received SIGHUP, reloading configuration")
+		r.reload(ctx)
+	}
+}
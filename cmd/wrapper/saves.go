@@ -0,0 +1,142 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/state"
+)
+
+// savesCmd is the parent command for inspecting saves.
+var savesCmd = &cobra.Command{
+	Use:   "saves",
+	Short: "Inspect the server's saves",
+}
+
+// savesListCmd lists every save along with information from its header.
+var savesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists every save, most recent (loaded by the server) first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		showMods, _ := cmd.Flags().GetBool("mods") //nolint:errcheck // Why: Defaults.
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Open(downloader.StatePath(cfg))
+		if err != nil {
+			return err
+		}
+
+		saves, err := factorio.ListSaves(cfg.ServerDataPath)
+		if err != nil {
+			return err
+		}
+
+		var warnings []string
+		headers := make([]*factorio.SaveHeader, len(saves))
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tVERSION\tSCENARIO\tMODS\tMODIFIED")
+		for i := range saves {
+			s := &saves[i]
+			version, scenario, mods := "unknown", "unknown", "unknown"
+
+			h, err := factorio.ReadSaveHeader(s.Path)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+			} else {
+				headers[i] = h
+				version = h.Version.String()
+				if h.Scenario != "" {
+					scenario = h.BaseMod + "/" + h.Scenario
+				}
+				if h.Mods != nil {
+					mods = fmt.Sprint(len(h.Mods))
+				}
+
+				if w, ok := checkSaveVersion(s, h, st.Version); !ok {
+					warnings = append(warnings, w)
+				}
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.Name(), version, scenario, mods, s.ModTime.Format(time.DateTime))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if showMods {
+			if err := printSaveMods(saves, headers); err != nil {
+				return err
+			}
+		}
+
+		for _, w := range warnings {
+			fmt.Fprintln(os.Stderr, "warning:", w)
+		}
+		return nil
+	},
+}
+
+// checkSaveVersion returns a warning if the save s, with header h,
+// can't be loaded by the installed version of Factorio.
+func checkSaveVersion(s *factorio.Save, h *factorio.SaveHeader, installed string) (string, bool) {
+	iv, err := factorio.ParseVersion(installed)
+	if err != nil || factorio.CanLoad(h, iv) {
+		return "", true
+	}
+
+	return fmt.Sprintf("%s was saved with Factorio %s, but %s is installed and can't load it",
+		s.Name(), h.Version, installed), false
+}
+
+// printSaveMods prints the mods each save was made with, skipping
+// saves whose header couldn't be read or doesn't list its mods.
+func printSaveMods(saves []factorio.Save, headers []*factorio.SaveHeader) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i := range saves {
+		h := headers[i]
+		if h == nil || h.Mods == nil {
+			continue
+		}
+
+		fmt.Fprintf(tw, "\n%s:\n", saves[i].Name())
+		for _, m := range h.Mods {
+			fmt.Fprintf(tw, "  %s\t%s\n", m.Name, m.Version)
+		}
+	}
+	return tw.Flush()
+}
+
+func init() {
+	savesListCmd.Flags().Bool("mods", false, "Also print the name and version of every mod each save was made with.")
+	savesCmd.AddCommand(savesListCmd)
+	rootCmd.AddCommand(savesCmd)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+)
+
+// upgrader restarts the server on a newer version of Factorio when the
+// requested channel or constraint resolves to one.
+type upgrader struct {
+	log *slog.Logger
+	cfg config.AutoUpgrade
+
+	// requested is the version from the configuration, before it was
+	// resolved to an exact version.
+	requested string
+}
+
+// newUpgrader returns an upgrader for the provided configuration. It
+// must be called before the version in cfg is resolved.
+func newUpgrader(log *slog.Logger, cfg *config.Config) *upgrader {
+	return &upgrader{log: log, cfg: cfg.AutoUpgrade, requested: cfg.Version}
+}
+
+// newerVersion returns the version the requested version resolves to,
+// if it is newer than installed and wasn't rolled back before.
+func (u *upgrader) newerVersion(cfg *config.Config, installed string) (string, bool, error) {
+	latest, err := downloader.ResolveVersion(u.requested)
+	if err != nil {
+		return "", false, err
+	}
+
+	bad, err := downloader.IsBadVersion(cfg, latest)
+	if err != nil || bad {
+		return "", false, err
+	}
+
+	lv, err := factorio.ParseVersion(latest)
+	if err != nil {
+		return "", false, err
+	}
+	iv, err := factorio.ParseVersion(installed)
+	if err != nil {
+		return "", false, err
+	}
+
+	return latest, iv.LessThan(lv), nil
+}
+
+// watch checks for a newer version than installed every interval,
+// until the context is canceled. Once one is found, it waits for the
+// server to be empty or warns the players online, depending on the
+// configured mode, then saves the server and calls stop.
+func (u *upgrader) watch(ctx context.Context, cfg *config.Config, srv *launcher.Server, installed string,
+	stop func()) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(u.cfg.Interval):
+		}
+
+		version, ok, err := u.newerVersion(cfg, installed)
+		if err != nil {
+			u.log.Warn("Failed to check for a new version of Factorio", "err", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		u.log.Info("New version of Factorio available", "version", version, "installed", installed)
+		if !srv.WaitForUpgrade(ctx, u.log, u.cfg, version) {
+			return
+		}
+
+		// Save explicitly, so that a failed save doesn't lose progress.
+		if err := srv.Save(ctx, u.log); err != nil {
+			u.log.Error("Not upgrading", "err", err)
+			continue
+		}
+
+		u.log.Info("Stopping server to upgrade Factorio", "version", version)
+		stop()
+		return
+	}
+}
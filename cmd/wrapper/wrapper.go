@@ -25,45 +25,208 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
-	charmlog "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
 
 	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/discord"
 	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
 	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"github.com/jaredallard/factorio-docker/internal/notify"
 )
 
-// entrypoint is the entrypoint fro the wrapper CLI.
-func entrypoint() error {
-	handler := charmlog.New(os.Stderr)
-	log := slog.New(handler)
-
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
+// rootCmd is the root command for the wrapper CLI. When ran without a
+// subcommand, it installs and runs the Factorio server.
+var rootCmd = &cobra.Command{
+	Use:           "wrapper",
+	Short:         "Configures and runs a Factorio server",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return entrypoint(cmd.Context())
+	},
+}
 
+// entrypoint is the entrypoint fro the wrapper CLI.
+func entrypoint(ctx context.Context) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
+	// Catch configuration mistakes before doing anything else.
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	log := newLogger(os.Stderr, cfg)
+
 	log.Info("Starting...", "version", cfg.Version, "server_path", cfg.InstallPath, "data_path", cfg.ServerDataPath)
+	if cfg.ConfigFile != "" {
+		log.Info("Loaded configuration file", "path", cfg.ConfigFile)
+	}
+	for _, d := range cfg.Deprecations() {
+		log.Warn(d)
+	}
 
-	// Ensure that we're using the requested version.
-	log.Info("Checking installed Factorio version")
-	if err := downloader.EnsureVersion(ctx, cfg, log); err != nil {
+	n, err := notify.New(log.With("component", "notify"), cfg.Webhooks)
+	if err != nil {
 		return err
 	}
 
-	// Launch the Factorio server.
-	return launcher.Launch(ctx, log, cfg)
+	// Events are sent even after we've been asked to stop, so that
+	// webhooks are told about it.
+	go n.Run(context.WithoutCancel(ctx))
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		n.Close(closeCtx)
+		cancel()
+	}()
+
+	var bridge *discord.Bridge
+	if cfg.Discord.Enabled {
+		bridge = discord.New(log.With("component", "discord"), cfg.Discord)
+		go bridge.Run(ctx)
+	}
+
+	// Re-read the configuration when asked to and keep player lists in
+	// sync.
+	r := newReloader(log, cfg, n, bridge)
+	go r.run(ctx)
+	go r.syncPlayers(ctx)
+
+	// The server is restarted in place whenever it is upgraded or rolled
+	// back.
+	u := newUpgrader(log.With("component", "upgrader"), cfg)
+	for {
+		// Use the configuration as last reloaded, so that restarts don't
+		// undo rotated secrets, and ensure that we're using the requested
+		// version.
+		cfg = r.config()
+		cfg.Version = u.requested
+		log.Info("Checking installed Factorio version")
+		if err := downloader.EnsureVersion(ctx, cfg, log, n); err != nil {
+			return err
+		}
+
+		var restart bool
+		restart, err = launch(ctx, log, cfg, n, r, u, bridge)
+		if err != nil || !restart || ctx.Err() != nil {
+			break
+		}
+		log.Info("Restarting Factorio server")
+	}
+
+	if bridge != nil {
+		// The context is likely canceled by now, but the notice should
+		// still be sent.
+		notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		bridge.NotifyStopped(notifyCtx, err)
+		cancel()
+	}
+
+	return err
+}
+
+// launch runs the Factorio server until it exits or the context is
+// canceled, returning true if it should be restarted because it was
+// upgraded by u or rolled back. After an upgrade, the server is rolled
+// back if it exits or hasn't started within cfg.RollbackTimeout.
+func launch(ctx context.Context, log *slog.Logger, cfg *config.Config, n *notify.Notifier, r *reloader,
+	u *upgrader, bridge *discord.Bridge) (bool, error) {
+	serverCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var upgrade, ready atomic.Bool
+	installed := cfg.Version
+
+	probation := false
+	if cfg.RollbackTimeout > 0 {
+		var err error
+		probation, err = downloader.CanRollback(cfg)
+		if err != nil {
+			return false, err
+		}
+	}
+	if probation {
+		log.Info("Factorio was upgraded, waiting for the server to start", "timeout", cfg.RollbackTimeout)
+		t := time.AfterFunc(cfg.RollbackTimeout, func() {
+			if !ready.Load() {
+				log.Error("Factorio server didn't start in time after upgrading", "timeout", cfg.RollbackTimeout)
+				stop()
+			}
+		})
+		defer t.Stop()
+	}
+
+	// Launch the Factorio server, recording when it has started
+	// successfully on the installed version.
+	err := launcher.Launch(serverCtx, log, cfg, n, launcher.Hooks{
+		OnReady: func(srv *launcher.Server) {
+			// Only act on the first ready line, so that the upgrade watcher
+			// and start notices never run more than once per launch.
+			if ready.Swap(true) {
+				return
+			}
+			log.Info("Factorio server is ready")
+			if err := downloader.MarkStarted(ctx, cfg); err != nil {
+				log.Warn("Failed to record successful start", "err", err)
+			}
+			go r.setServer(ctx, srv)
+
+			if bridge != nil {
+				bridge.SetServer(srv)
+				bridge.NotifyStarted()
+			}
+
+			if cfg.AutoUpgrade.Enabled {
+				go u.watch(serverCtx, cfg, srv, installed, func() {
+					upgrade.Store(true)
+					stop()
+				})
+			}
+		},
+		OnConsoleEvent: func(ev factorio.ConsoleEvent) {
+			if bridge != nil {
+				bridge.HandleConsoleEvent(ev)
+			}
+		},
+	})
+
+	if probation && !ready.Load() && ctx.Err() == nil {
+		if err != nil {
+			log.Error("Factorio server failed after upgrading", "err", err)
+		}
+		if err := downloader.Rollback(ctx, cfg, log, n); err != nil {
+			return false, fmt.Errorf("failed to roll back upgrade: %w", err)
+		}
+
+		// A version requested explicitly is installed again on restart,
+		// so stop instead of failing the same way forever.
+		if _, err := factorio.ParseVersion(u.requested); err == nil {
+			return false, fmt.Errorf("rolled back Factorio %s since it failed to start, but %s requests it explicitly",
+				installed, cfg.EnvName(&cfg.Version))
+		}
+		return true, nil
+	}
+
+	return upgrade.Load(), err
 }
 
-// main runs the entrypoint function. If it returns a non-nil error, it
-// exits the program with a status code of 1. This is done to prevent
-// `defer` from swallowing panics.
+// main runs the root command. If it returns a non-nil error, it exits
+// the program with a status code of 1. This is done to prevent `defer`
+// from swallowing panics.
 func main() {
-	if err := entrypoint(); err != nil {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	cancel()
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,89 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// Args is a list of command line arguments. In environment variables, it
+// is parsed using shell quoting rules, e.g., `--foo "bar baz"`.
+type Args []string
+
+// argsType is the type of Args.
+var argsType = reflect.TypeOf(Args(nil))
+
+// UnmarshalText parses shell quoted arguments.
+func (a *Args) UnmarshalText(text []byte) error {
+	args, err := shlex.Split(string(text))
+	if err != nil {
+		return fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	*a = args
+	return nil
+}
+
+// shellQuote quotes s so that it is parsed as a single argument by
+// Args.UnmarshalText.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// managedFlags are the flags set by the launcher, which can't be passed
+// as extra arguments.
+var managedFlags = []string{
+	"-c", "--config",
+	"--server-settings",
+	"--server-banlist",
+	"--server-whitelist",
+	"--server-adminlist",
+	"--server-id",
+	"--use-server-whitelist",
+	"--start-server",
+	"--start-server-load-latest",
+	"--start-server-load-scenario",
+	"--port",
+	"--bind",
+	"--rcon-port",
+	"--rcon-bind",
+	"--rcon-password",
+}
+
+// consoleLogFlag is the flag the launcher sets when Logs.Enabled is
+// true.
+const consoleLogFlag = "--console-log"
+
+// managedFlag returns the flag arg sets if it is managed by the
+// launcher with the configuration in c.
+func (c *Config) managedFlag(arg string) (string, bool) {
+	name, _, _ := strings.Cut(arg, "=")
+	for _, f := range managedFlags {
+		if name == f {
+			return f, true
+		}
+	}
+	if c.Logs.Enabled && name == consoleLogFlag {
+		return consoleLogFlag, true
+	}
+	return "", false
+}
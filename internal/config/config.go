@@ -20,12 +20,41 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
 	"github.com/caarlos0/env/v11"
+
+	"github.com/jaredallard/factorio-docker/internal/players"
 )
 
-// Config is the configuration for the wrapper.
-// TODO(jaredallard): Generate documentation from the struct.
+// Config is the configuration for the wrapper. Documentation for every
+// field is generated from this struct by running go generate, so keep
+// field comments accurate.
+//
+// Every secret (fields with the "unset" option) can also be read from a
+// file by setting the environment variable with a _FILE suffix to its
+// path, e.g., FACTORIO_TOKEN_FILE.
 type Config struct {
+	// secretFiles contains the paths of secrets read from files, keyed
+	// by the secret's environment variable.
+	secretFiles map[string]string
+
+	// deprecations contains a warning for every deprecated setting used.
+	deprecations []string
+
+	// ConfigFile is the path to a YAML, TOML or JSON configuration file.
+	// If unset, wrapper.yaml (or .yml, .toml, .json) in ServerDataPath is
+	// used if it exists. Values in the file take precedence over
+	// defaults, while environment variables take precedence over the
+	// file. Keys are the lowercase environment variable names without the
+	// FACTORIO_ prefix, e.g., 'install_path', with prefixed groups as
+	// nested sections, e.g., 'discord.enabled'.
+	ConfigFile string `env:"CONFIG_FILE"`
+
 	// Username is the factorio.com username for who owns this server.
 	// This is only required for making a server public. For more
 	// information, see:
@@ -37,8 +66,55 @@ type Config struct {
 	// https://wiki.factorio.com/Multiplayer#How_to_list_a_server-hosted_game_on_the_matching_server
 	Token string `env:"TOKEN,unset"`
 
-	// Factocord is configuration for running Factocord.
-	Factocord Factocord `envPrefix:"FACTOCORD_"`
+	// GamePassword is the password required to join the server. If set,
+	// it overrides the password in server-settings.json.
+	GamePassword string `env:"GAME_PASSWORD,unset"`
+
+	// RCONPassword is the password for the RCON interface. If set, RCON
+	// can be used from outside of the container.
+	RCONPassword string `env:"RCON_PASSWORD,unset"`
+
+	// Port is the UDP port the game listens on.
+	Port int `env:"PORT" envDefault:"34197"`
+
+	// BindAddress is the IP address the game listens on. If not set, it
+	// listens on every interface.
+	BindAddress string `env:"BIND_ADDRESS"`
+
+	// RCONPort is the TCP port RCON listens on.
+	RCONPort int `env:"RCON_PORT" envDefault:"27015"`
+
+	// RCONBind is the IP address RCON listens on. If not set, RCON
+	// listens on every interface when RCONPassword is set, and only on
+	// localhost otherwise, where it is used by the wrapper with a random
+	// password.
+	RCONBind string `env:"RCON_BIND"`
+
+	// Players declares the admins, whitelisted and banned players of the
+	// server.
+	Players Players `envPrefix:"PLAYERS_"`
+
+	// Discord is configuration for the Discord bridge.
+	Discord Discord `envPrefix:"DISCORD_"`
+
+	// LogFormat is the format of the wrapper's own log output, which
+	// also includes the output of the server. One of 'text' (human
+	// readable), 'json' or 'logfmt'.
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text"`
+
+	// LogLevel is the minimum level of log messages to output. One of
+	// 'debug', 'info', 'warn' or 'error'.
+	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+
+	// Logs is configuration for the server logs kept in ServerDataPath.
+	Logs Logs `envPrefix:"LOGS_"`
+
+	// Webhooks is configuration for sending server events to webhooks.
+	Webhooks Webhooks `envPrefix:"WEBHOOK_"`
+
+	// AutoUpgrade is configuration for upgrading Factorio while the
+	// server is running.
+	AutoUpgrade AutoUpgrade `envPrefix:"AUTO_UPGRADE_"`
 
 	// InstallPath is the location to install Factorio to. This is NOT
 	// where the save files are stored.
@@ -52,35 +128,295 @@ type Config struct {
 	// 'stable' or 'experimental', the latest version of that channel will
 	// be used. Note that this means it will also be updated on every
 	// restart. If set to a specific version, that version will be used.
+	// If set to a constraint (e.g., '~2.0' or '>=1.1.100 <2.0'), the
+	// newest published release satisfying it will be used.
 	Version string `env:"VERSION" envDefault:"stable"`
+
+	// ExtraArgs are extra command line arguments passed to Factorio,
+	// after the ones set by the wrapper. In the environment variable,
+	// they are parsed using shell quoting rules (e.g., '--mod-directory
+	// "/data/my mods"'), while the configuration file accepts a list.
+	// Flags set by the wrapper, such as --server-settings or --port, are
+	// rejected, as is --console-log unless Logs.Enabled is false.
+	ExtraArgs Args `env:"EXTRA_ARGS"`
+
+	// INI contains overrides for Factorio's config.ini, keyed by section
+	// and then key. They're set through FACTORIO_INI__<section>__<key>
+	// environment variables or the 'ini' section of the configuration
+	// file, and applied on every start. Environment variables take
+	// precedence over the configuration file.
+	INI map[string]map[string]string
+
+	// INIReset determines whether config.ini is reset to Factorio's
+	// defaults on every start, before INI overrides are applied. This
+	// discards any changes made to it by hand.
+	INIReset bool `env:"INI_RESET" envDefault:"false"`
+
+	// AllowDowngrade allows installing a version of Factorio older than
+	// the one the save loaded by the server was made with. Older
+	// versions can't load saves made by newer ones, so this is refused
+	// by default.
+	AllowDowngrade bool `env:"ALLOW_DOWNGRADE" envDefault:"false"`
+
+	// VerifyInstall controls how the installed Factorio server is checked
+	// for corruption on startup. If set to 'fast', file sizes and
+	// modification times are compared. If set to 'full', every file is
+	// hashed. If set to 'off', no checks are done. If a check fails,
+	// Factorio is reinstalled.
+	VerifyInstall string `env:"VERIFY_INSTALL" envDefault:"fast"`
+
+	// RollbackTimeout is how long the server has to start after Factorio
+	// is upgraded. If it exits or hasn't started in time, the previous
+	// version and the save from before the upgrade are restored, and the
+	// new version is skipped until a newer one is released. If set to
+	// '0', upgrades are not rolled back.
+	RollbackTimeout time.Duration `env:"ROLLBACK_TIMEOUT" envDefault:"10m"`
+}
+
+// Players declares the admins, whitelisted and banned players of the
+// server. Each list is only managed if it is set, even if it is set to
+// an empty list. Managed lists are written to the server data path on
+// startup and applied to the running server over RCON on SIGHUP, so
+// players added or removed in-game are reverted. Lists that are not set
+// are left to be managed in-game.
+type Players struct {
+	// Admins is a comma separated list of players that are server
+	// admins.
+	Admins []string `env:"ADMINS"`
+
+	// Whitelist is a comma separated list of players that are allowed to
+	// join the server.
+	Whitelist []string `env:"WHITELIST"`
+
+	// WhitelistMode controls whether only whitelisted players can join
+	// the server. If set to 'on', the whitelist is always enforced. If
+	// set to 'off', anyone can join. If set to 'auto', the whitelist is
+	// only enforced when it contains at least one player.
+	WhitelistMode string `env:"WHITELIST_MODE" envDefault:"auto"`
+
+	// SyncSource is an HTTP(S) URL or the path to a JSON file to
+	// periodically read player lists from. It must contain an object
+	// with the optional keys 'admins', 'whitelist' and 'bans', in the
+	// same format as Factorio's player list files. Lists in the source
+	// take precedence over the ones set in the configuration.
+	SyncSource string `env:"SYNC_SOURCE"`
+
+	// SyncInterval is how often to read player lists from SyncSource,
+	// e.g., '5m' or '1h'.
+	SyncInterval time.Duration `env:"SYNC_INTERVAL" envDefault:"5m"`
+
+	// Bans is a semicolon separated list of banned players, in the format
+	// 'username' or 'username:reason'.
+	Bans []players.Ban `env:"BANS" envSeparator:";"`
+}
+
+// SlogLevel returns LogLevel as a slog.Level.
+func (c *Config) SlogLevel() (slog.Level, error) {
+	switch c.LogLevel {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+
+	return 0, fmt.Errorf("unknown log level %q", c.LogLevel)
+}
+
+// Lists returns the declared player lists. Lists that are not managed
+// are nil.
+func (p *Players) Lists() *players.Lists {
+	return &players.Lists{Admins: p.Admins, Whitelist: p.Whitelist, Bans: p.Bans}
+}
+
+// Discord is the configuration for the Discord bridge, which relays
+// chat between the game and a Discord channel.
+type Discord struct {
+	// Enabled determines whether the Discord bridge is enabled.
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+
+	// Token is the Bot user token to use for the Discord API. The bot
+	// needs the Message Content intent.
+	Token string `env:"TOKEN,unset"`
+
+	// ChannelID is the ID of the Discord channel to relay chat to and
+	// from.
+	ChannelID string `env:"CHANNEL_ID"`
+
+	// AdminRoleIDs is a comma separated list of IDs of Discord roles
+	// allowed to run admin commands (e.g., '!kick') in the channel.
+	AdminRoleIDs []string `env:"ADMIN_ROLE_IDS"`
+}
+
+// Logs is the configuration for the server logs kept in the 'logs'
+// directory of ServerDataPath, which can be read with 'wrapper logs'.
+type Logs struct {
+	// Enabled determines whether the output of the server and its
+	// console log are written to the 'logs' directory.
+	Enabled bool `env:"ENABLED" envDefault:"true"`
+
+	// MaxSize is the size in megabytes at which the server output log is
+	// rotated. Rotated logs are compressed with gzip.
+	MaxSize int `env:"MAX_SIZE" envDefault:"10"`
+
+	// MaxAge is how long to keep rotated logs for, e.g., '336h'. It is
+	// rounded up to whole days. If set to '0', they're kept regardless
+	// of age.
+	MaxAge time.Duration `env:"MAX_AGE" envDefault:"336h"`
+
+	// MaxBackups is how many rotated logs of each kind to keep. If set
+	// to 0, they're kept regardless of count.
+	MaxBackups int `env:"MAX_BACKUPS" envDefault:"10"`
 }
 
-// Factocord is the configuration for running Factocord, which provides
-// Discord integration for Factorio.
-type Factocord struct {
+// AutoUpgrade is the configuration for upgrading Factorio while the
+// server is running, when the channel or constraint in Version resolves
+// to a newer version than the one installed.
+type AutoUpgrade struct {
+	// Enabled determines whether to check for new versions of Factorio
+	// while the server is running. If a newer version is found, the
+	// server is saved, stopped, upgraded and started again. Requires
+	// Version to be a channel or constraint.
 	Enabled bool `env:"ENABLED" envDefault:"false"`
 
-	// DiscordToken is the Bot user token to use for the Discord API.
-	DiscordToken string `env:"DISCORD_TOKEN,unset"`
+	// Interval is how often to check for a new version.
+	Interval time.Duration `env:"INTERVAL" envDefault:"1h"`
+
+	// Mode controls what happens when players are online once a new
+	// version is found. If set to 'wait', the upgrade waits until no
+	// players are online. If set to 'countdown', players are warned and
+	// the server is upgraded once Countdown has passed.
+	Mode string `env:"MODE" envDefault:"wait"`
+
+	// Countdown is how long players are warned for before the server is
+	// upgraded, when Mode is 'countdown'.
+	Countdown time.Duration `env:"COUNTDOWN" envDefault:"5m"`
+}
+
+// Webhooks is the configuration for sending server events, such as the
+// server starting or a player joining, to webhooks.
+type Webhooks struct {
+	// URLs is a comma separated list of URLs to POST events to. If not
+	// set, no webhooks are sent.
+	URLs []string `env:"URLS"`
+
+	// TemplateDir is a directory containing a Go template for the body
+	// of each event, named '<event>.tmpl' (e.g., 'player_joined.tmpl').
+	// Events without a template are sent as JSON. Supported events are
+	// server_started, server_stopped, server_crashed, version_upgraded,
+	// version_downgraded, version_rolled_back, player_joined, player_left,
+	// backup_succeeded and backup_failed.
+	TemplateDir string `env:"TEMPLATE_DIR"`
 
-	// DiscordChannelID is the ID of the Discord channel to send messages to.
-	DiscordChannelID string `env:"DISCORD_CHANNEL_ID"`
+	// ContentType is the Content-Type of the webhook requests.
+	ContentType string `env:"CONTENT_TYPE" envDefault:"application/json"`
 
-	// DiscordUserColors determines whether to use user colors in Discord messages.
-	DiscordUserColors bool `env:"DISCORD_USER_COLORS" envDefault:"true"`
+	// Secret is used to sign the body of every request with
+	// HMAC-SHA256. The signature is sent in the X-Signature-256 header
+	// as 'sha256=<hex>'.
+	Secret string `env:"SECRET,unset"`
+
+	// Retries is how many times to retry sending an event that failed
+	// with a network or server error.
+	Retries int `env:"RETRIES" envDefault:"3"`
+
+	// Timeout is how long to wait for a webhook to respond.
+	Timeout time.Duration `env:"TIMEOUT" envDefault:"10s"`
 }
 
-// Load loads the configuration from the environment.
+// Load loads the configuration from the configuration file, if present,
+// and the environment. Environment variables take precedence over the
+// configuration file, which takes precedence over defaults.
 func Load() (*Config, error) {
 	var cfg Config
-	err := env.ParseWithOptions(&cfg, env.Options{
+
+	environ := env.ToMap(os.Environ())
+	deprecations := renameLegacyEnv(environ, fields(&cfg))
+	path, err := findConfigFile(environ, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]string)
+	var fileINI map[string]map[string]string
+	if path != "" {
+		var fileEnviron map[string]string
+		fileEnviron, fileINI, err = readConfigFile(path, &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range fileEnviron {
+			merged[k] = v
+		}
+	}
+	for k, v := range environ {
+		merged[k] = v
+	}
+
+	secretFiles, err := resolveSecretFiles(merged, fields(&cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	err = env.ParseWithOptions(&cfg, env.Options{
 		// Prefix all configuration variables with FACTORIO_ to prevent
 		// accidental conflicts with other environment variables.
-		Prefix: "FACTORIO_",
+		Prefix:      envPrefix,
+		Environment: merged,
 	})
 	if err != nil {
 		return nil, err
 	}
+	declareLists(merged, fields(&cfg))
+
+	cfg.INI, err = parseINIOverrides(fileINI, environ)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConfigFile = path
+	cfg.secretFiles = secretFiles
+	cfg.deprecations = deprecations
 
 	return &cfg, nil
 }
+
+// declareLists sets every list that was provided, but empty, to an empty
+// slice so that it can be told apart from a list that wasn't provided.
+func declareLists(environ map[string]string, fs []field) {
+	for _, f := range fs {
+		if f.v.Kind() != reflect.Slice || !f.v.IsNil() {
+			continue
+		}
+
+		if _, ok := environ[f.env]; ok {
+			f.v.Set(reflect.MakeSlice(f.v.Type(), 0, 0))
+		}
+	}
+}
+
+// Reload loads the configuration again, returning the new configuration.
+// Secrets that were provided through the environment are removed from
+// it once read, so they are carried over from the receiver.
+func (c *Config) Reload() (*Config, error) {
+	n, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	nfs, ofs := fields(n), fields(c)
+	for i := range nfs {
+		if !nfs[i].secret || nfs[i].v.String() != "" {
+			continue
+		}
+
+		if _, ok := c.secretFiles[nfs[i].env]; !ok {
+			nfs[i].v.Set(ofs[i].v)
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,257 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"gotest.tools/v3/assert"
+)
+
+func TestConfigFilePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", dir)
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "wrapper.yaml"), []byte(`
+version: "~2.0"
+install_path: /srv/factorio
+discord:
+  enabled: true
+  token: from-file
+`), 0o600))
+
+	// Environment variables take precedence over the file.
+	t.Setenv("FACTORIO_INSTALL_PATH", "/from/env")
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.ConfigFile, filepath.Join(dir, "wrapper.yaml"))
+	assert.Equal(t, cfg.Version, "~2.0")
+	assert.Equal(t, cfg.InstallPath, "/from/env")
+	assert.Equal(t, cfg.Discord.Enabled, true)
+	assert.Equal(t, cfg.Discord.Token, "from-file")
+
+	// Defaults are used when neither sets a value.
+	assert.Equal(t, cfg.VerifyInstall, "fast")
+
+	b, err := cfg.MarshalRedacted()
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), "from-file"), string(b))
+}
+
+func TestConfigFileFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"wrapper.toml": "version = \"1.1.110\"\n[discord]\nenabled = true\n",
+		"wrapper.json": `{"version": "1.1.110", "discord": {"enabled": true}}`,
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.WriteFile(path, []byte(contents), 0o600))
+		t.Setenv("FACTORIO_CONFIG_FILE", path)
+
+		cfg, err := config.Load()
+		assert.NilError(t, err, name)
+		assert.Equal(t, cfg.Version, "1.1.110", name)
+		assert.Equal(t, cfg.Discord.Enabled, true, name)
+	}
+}
+
+func TestConfigFileRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wrapper.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte("discord:\n  enbaled: true\n"), 0o600))
+	t.Setenv("FACTORIO_CONFIG_FILE", path)
+
+	_, err := config.Load()
+	assert.ErrorContains(t, err, `unknown key "discord.enbaled"`)
+}
+
+func TestSecretsCanBeReadFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	discordPath := filepath.Join(dir, "discord")
+	assert.NilError(t, os.WriteFile(tokenPath, []byte("hunter2\n"), 0o600))
+	assert.NilError(t, os.WriteFile(discordPath, []byte("bot-token"), 0o600))
+
+	configPath := filepath.Join(dir, "wrapper.yaml")
+	assert.NilError(t, os.WriteFile(configPath, []byte("discord:\n  token_file: "+discordPath+"\n"), 0o600))
+	t.Setenv("FACTORIO_CONFIG_FILE", configPath)
+	t.Setenv("FACTORIO_TOKEN_FILE", tokenPath)
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.Token, "hunter2")
+	assert.Equal(t, cfg.Discord.Token, "bot-token")
+
+	// Secrets are re-read without modifying the original configuration.
+	assert.NilError(t, os.WriteFile(tokenPath, []byte("hunter3\n"), 0o600))
+	reloaded, err := cfg.Reload()
+	assert.NilError(t, err)
+	assert.Equal(t, reloaded.Token, "hunter3")
+	assert.Equal(t, cfg.Token, "hunter2")
+}
+
+func TestLegacyFactocordEnvIsRenamed(t *testing.T) {
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", t.TempDir())
+	t.Setenv("FACTORIO_FACTOCORD_ENABLED", "true")
+	t.Setenv("FACTORIO_FACTOCORD_DISCORD_TOKEN", "legacy-token")
+	t.Setenv("FACTORIO_FACTOCORD_DISCORD_CHANNEL_ID", "legacy")
+	t.Setenv("FACTORIO_FACTOCORD_DISCORD_USER_COLORS", "true")
+	t.Setenv("FACTORIO_DISCORD_CHANNEL_ID", "1234")
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.Discord.Enabled, true)
+	assert.Equal(t, cfg.Discord.Token, "legacy-token")
+	assert.Equal(t, cfg.Discord.ChannelID, "1234")
+	assert.Equal(t, len(cfg.Deprecations()), 4)
+	assert.Equal(t, cfg.Deprecations()[3], "FACTORIO_FACTOCORD_DISCORD_USER_COLORS is no longer supported and is ignored")
+
+	_, ok := os.LookupEnv("FACTORIO_FACTOCORD_DISCORD_TOKEN")
+	assert.Assert(t, !ok, "legacy secrets should be removed from the environment")
+}
+
+func TestSecretAndSecretFileConflict(t *testing.T) {
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", t.TempDir())
+	t.Setenv("FACTORIO_RCON_PASSWORD", "a")
+	t.Setenv("FACTORIO_RCON_PASSWORD_FILE", "/nonexistent")
+
+	_, err := config.Load()
+	assert.ErrorContains(t, err, "only one of FACTORIO_RCON_PASSWORD and FACTORIO_RCON_PASSWORD_FILE")
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", dir)
+	t.Setenv("FACTORIO_INSTALL_PATH", filepath.Join(dir, "install", "created", "later"))
+	t.Setenv("FACTORIO_VERSION", "2.0.x.1")
+	t.Setenv("FACTORIO_VERIFY_INSTALL", "sometimes")
+	t.Setenv("FACTORIO_DISCORD_ENABLED", "true")
+	t.Setenv("FACTORIO_LOG_FORMAT", "xml")
+	t.Setenv("FACTORIO_LOG_LEVEL", "loud")
+	t.Setenv("FACTORIO_AUTO_UPGRADE_ENABLED", "true")
+	t.Setenv("FACTORIO_AUTO_UPGRADE_MODE", "never")
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "FACTORIO_VERSION: ")
+	assert.ErrorContains(t, err, "FACTORIO_VERIFY_INSTALL: ")
+	assert.ErrorContains(t, err, "FACTORIO_DISCORD_TOKEN: ")
+	assert.ErrorContains(t, err, "FACTORIO_DISCORD_CHANNEL_ID: ")
+	assert.ErrorContains(t, err, "FACTORIO_LOG_FORMAT: ")
+	assert.ErrorContains(t, err, "FACTORIO_LOG_LEVEL: ")
+	assert.ErrorContains(t, err, "FACTORIO_AUTO_UPGRADE_MODE: ")
+	assert.Assert(t, !strings.Contains(err.Error(), "FACTORIO_INSTALL_PATH"), err.Error())
+	assert.Assert(t, !strings.Contains(err.Error(), "FACTORIO_SERVER_DATA_PATH"), err.Error())
+}
+
+func TestValidateAcceptsConstraints(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", dir)
+	t.Setenv("FACTORIO_INSTALL_PATH", dir)
+	t.Setenv("FACTORIO_VERSION", ">=1.1.100 <2.0")
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.NilError(t, cfg.Validate())
+}
+
+func TestPlayerListsAreOnlyManagedWhenSet(t *testing.T) {
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", t.TempDir())
+	t.Setenv("FACTORIO_PLAYERS_ADMINS", "")
+	t.Setenv("FACTORIO_PLAYERS_BANS", "eve:griefing, again;mallory")
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+
+	l := cfg.Players.Lists()
+	assert.Assert(t, l.Admins != nil && len(l.Admins) == 0)
+	assert.Assert(t, l.Whitelist == nil)
+	assert.DeepEqual(t, l.Bans, []players.Ban{
+		{Username: "eve", Reason: "griefing, again"},
+		{Username: "mallory"},
+	})
+}
+
+func TestINIOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", dir)
+	t.Setenv("FACTORIO_INI__other__non_blocking_saving", "true")
+	t.Setenv("FACTORIO_INI__other__autosave_compression_level", "max")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "wrapper.yaml"), []byte(`
+ini:
+  other:
+    port: 34198
+    autosave-compression-level: low
+  map-view:
+    show-grid: false
+    some_key: kept
+`), 0o600))
+
+	// Keys from the configuration file are used as written, and the
+	// environment takes precedence over it.
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg.INI, map[string]map[string]string{
+		"other":    {"port": "34198", "non-blocking-saving": "true", "autosave-compression-level": "max"},
+		"map-view": {"show-grid": "false", "some_key": "kept"},
+	})
+
+	t.Setenv("FACTORIO_INI__path__write_data", "/elsewhere")
+	_, err = config.Load()
+	assert.ErrorContains(t, err, "path.write-data is managed by the wrapper")
+}
+
+func TestExtraArgs(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FACTORIO_SERVER_DATA_PATH", dir)
+	t.Setenv("FACTORIO_INSTALL_PATH", dir)
+	t.Setenv("FACTORIO_EXTRA_ARGS", `--console-log "/data/console log.txt" --mod-directory /mods`)
+
+	cfg, err := config.Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg.ExtraArgs, config.Args{"--console-log", "/data/console log.txt", "--mod-directory", "/mods"})
+
+	// The console log is only managed while logs are enabled.
+	assert.ErrorContains(t, cfg.Validate(), "FACTORIO_EXTRA_ARGS: --console-log is set by the wrapper")
+	t.Setenv("FACTORIO_LOGS_ENABLED", "false")
+	cfg, err = config.Load()
+	assert.NilError(t, err)
+	assert.NilError(t, cfg.Validate())
+
+	// Lists in the configuration file are passed as-is.
+	os.Unsetenv("FACTORIO_EXTRA_ARGS")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "wrapper.yaml"),
+		[]byte("extra_args: [--console-log, \"/data/it's a log.txt\", --port=1]\n"), 0o600))
+
+	cfg, err = config.Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg.ExtraArgs, config.Args{"--console-log", "/data/it's a log.txt", "--port=1"})
+
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "FACTORIO_EXTRA_ARGS: --port is set by the wrapper")
+}
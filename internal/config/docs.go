@@ -0,0 +1,205 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+//go:generate go run ./gendocs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldDocs contains the documentation of every field in Config, keyed
+// by "<struct>.<field>", e.g., "Discord.Enabled".
+type FieldDocs map[string]string
+
+// Docs returns the documentation of every field in Config, as extracted
+// from its source by go generate.
+func Docs() FieldDocs {
+	return fieldDocs
+}
+
+// docKey returns the key of a field in FieldDocs.
+func (f *field) docKey() string {
+	return f.owner.Name() + "." + f.sf.Name
+}
+
+// description returns the documentation for a field as a single line.
+func (d FieldDocs) description(f *field) string {
+	desc := strings.Join(strings.Fields(d[f.docKey()]), " ")
+	if f.secret {
+		desc += fmt.Sprintf(" This is a secret and can be read from a file set in %s%s.", f.env, secretFileSuffix)
+	}
+	return strings.TrimSpace(desc)
+}
+
+// Markdown returns a Markdown document describing every supported
+// configuration value, using the provided field documentation.
+func Markdown(docs FieldDocs) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<!-- Code generated by go generate ./internal/config. DO NOT EDIT. -->\n\n")
+	buf.WriteString("# Configuration\n\n")
+	buf.WriteString("Every value can be set through its environment variable or its key in the\n")
+	buf.WriteString("configuration file. Environment variables take precedence.\n\n")
+	buf.WriteString("| Environment Variable | Config File Key | Default | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, f := range fields(&Config{}) {
+		def := ""
+		if f.hasDefault {
+			def = "`" + f.def + "`"
+		}
+
+		fmt.Fprintf(&buf, "| `%s` | `%s` | %s | %s |\n", f.env, strings.Join(f.key, "."), def,
+			strings.ReplaceAll(docs.description(&f), "|", `\|`))
+	}
+
+	buf.WriteString("\n## config.ini Overrides\n\n")
+	buf.WriteString("Any value in Factorio's `config.ini` can be set with an environment variable\n")
+	buf.WriteString("named `" + iniEnvPrefix + "<section>" + iniSeparator + "<key>`, e.g.,\n")
+	buf.WriteString("`" + iniEnvPrefix + "other" + iniSeparator + "port=34198`, or in the `" + iniFileKey + "` section of the\n")
+	buf.WriteString("configuration file. Since most shells don't allow `-` in environment\n")
+	buf.WriteString("variables, `_` can be used instead, e.g.,\n")
+	buf.WriteString("`" + iniEnvPrefix + "other" + iniSeparator + "non_blocking_saving=true`. Keys in the configuration\n")
+	buf.WriteString("file are used as written, and environment variables take precedence over\n")
+	buf.WriteString("them.\n")
+
+	return buf.Bytes()
+}
+
+// JSONSchema returns a JSON Schema describing the configuration file,
+// using the provided field documentation.
+func JSONSchema(docs FieldDocs) ([]byte, error) {
+	root := newSchemaObject()
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "factorio-docker wrapper configuration"
+
+	for _, f := range fields(&Config{}) {
+		// Find or create the section for the field.
+		section := root
+		for _, k := range f.key[:len(f.key)-1] {
+			props := section["properties"].(map[string]any) //nolint:errcheck // Why: Always an object.
+			if _, ok := props[k]; !ok {
+				props[k] = newSchemaObject()
+			}
+			section = props[k].(map[string]any) //nolint:errcheck // Why: Always an object.
+		}
+
+		prop := schemaType(f.sf.Type)
+		if desc := docs.description(&f); desc != "" {
+			prop["description"] = desc
+		}
+		if f.hasDefault {
+			prop["default"] = schemaDefault(f.sf.Type, f.def)
+		}
+
+		name := f.key[len(f.key)-1]
+		props := section["properties"].(map[string]any) //nolint:errcheck // Why: Always an object.
+		props[name] = prop
+		if f.secret {
+			props[name+strings.ToLower(secretFileSuffix)] = map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("Path to a file containing %s.", name),
+			}
+		}
+	}
+
+	props := root["properties"].(map[string]any) //nolint:errcheck // Why: Always an object.
+	props[iniFileKey] = map[string]any{
+		"type":        "object",
+		"description": "Overrides for Factorio's config.ini, keyed by section and then key.",
+		"additionalProperties": map[string]any{
+			"type": "object",
+			"additionalProperties": map[string]any{
+				"type": []string{"string", "number", "boolean"},
+			},
+		},
+	}
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// newSchemaObject returns a JSON Schema for an object that does not
+// allow unknown properties.
+func newSchemaObject() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{},
+		"additionalProperties": false,
+	}
+}
+
+// durationType is the type of time.Duration, which is configured as a
+// string, e.g., '5m'.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// schemaDefault converts the default value of a field into the type
+// used by the JSON Schema.
+func schemaDefault(t reflect.Type, def string) any {
+	if t == durationType {
+		return def
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(def); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return i
+		}
+	}
+
+	return def
+}
+
+// schemaType returns the JSON Schema for a Go type.
+func schemaType(t reflect.Type) map[string]any {
+	if t == durationType {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		// Lists can also be provided in the same format used by the
+		// environment variable.
+		return map[string]any{"oneOf": []any{
+			map[string]any{"type": "array", "items": schemaType(t.Elem())},
+			map[string]any{"type": "string"},
+		}}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
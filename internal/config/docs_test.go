@@ -0,0 +1,44 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"gotest.tools/v3/assert"
+)
+
+// TestGeneratedDocsAreUpToDate ensures that the configuration reference
+// in the docs directory matches the Config struct. If this fails, run
+// go generate ./internal/config.
+func TestGeneratedDocsAreUpToDate(t *testing.T) {
+	md, err := os.ReadFile("../../docs/configuration.md")
+	assert.NilError(t, err)
+	assert.Equal(t, string(md), string(config.Markdown(config.Docs())),
+		"docs/configuration.md is out of date, run go generate ./internal/config")
+
+	wantSchema, err := config.JSONSchema(config.Docs())
+	assert.NilError(t, err)
+
+	schema, err := os.ReadFile("../../docs/config.schema.json")
+	assert.NilError(t, err)
+	assert.Equal(t, string(schema), string(wantSchema),
+		"docs/config.schema.json is out of date, run go generate ./internal/config")
+}
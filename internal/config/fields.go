@@ -0,0 +1,129 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// envPrefix is the prefix used for all environment variables.
+const envPrefix = "FACTORIO_"
+
+// field is a single configurable value in Config.
+type field struct {
+	// key is the path of the field in a configuration file, e.g.,
+	// ["discord", "enabled"].
+	key []string
+
+	// env is the name of the environment variable for the field,
+	// including the prefix, e.g., FACTORIO_DISCORD_ENABLED.
+	env string
+
+	// def is the default value of the field, if hasDefault is set.
+	def        string
+	hasDefault bool
+
+	// secret is true if the field contains a secret and should be
+	// redacted when displayed. Secrets are marked with the "unset"
+	// option so that they are removed from the environment once read.
+	secret bool
+
+	// owner is the struct type that contains the field.
+	owner reflect.Type
+
+	// sf is the struct field.
+	sf reflect.StructField
+
+	// v is the value of the field.
+	v reflect.Value
+}
+
+// separator returns the separator used for slice values in environment
+// variables.
+func (f *field) separator() string {
+	if f.sf.Type == argsType {
+		return " "
+	}
+	if sep := f.sf.Tag.Get("envSeparator"); sep != "" {
+		return sep
+	}
+	return ","
+}
+
+// fields returns every configurable field in cfg, in the order they are
+// declared.
+func fields(cfg *Config) []field {
+	return walkFields(reflect.ValueOf(cfg).Elem(), envPrefix, nil)
+}
+
+// walkFields returns every configurable field in the struct v.
+func walkFields(v reflect.Value, prefix string, key []string) []field {
+	var out []field
+
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if p, ok := sf.Tag.Lookup("envPrefix"); ok && sf.Type.Kind() == reflect.Struct {
+			sectionKey := strings.ToLower(strings.TrimSuffix(p, "_"))
+			out = append(out, walkFields(v.Field(i), prefix+p, appendKey(key, sectionKey))...)
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get("env"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		def, hasDefault := sf.Tag.Lookup("envDefault")
+		out = append(out, field{
+			key:        appendKey(key, strings.ToLower(name)),
+			env:        prefix + name,
+			def:        def,
+			hasDefault: hasDefault,
+			secret:     hasOption(opts, "unset"),
+			owner:      t,
+			sf:         sf,
+			v:          v.Field(i),
+		})
+	}
+
+	return out
+}
+
+// appendKey returns a copy of key with k appended to it.
+func appendKey(key []string, k string) []string {
+	out := make([]string, 0, len(key)+1)
+	out = append(out, key...)
+	return append(out, k)
+}
+
+// hasOption returns true if the comma separated options of an env tag
+// contain opt.
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFileNames are the names of the configuration files looked
+// for in the server data path, in order of preference.
+var defaultConfigFileNames = []string{"wrapper.yaml", "wrapper.yml", "wrapper.toml", "wrapper.json"}
+
+// findConfigFile returns the path to the configuration file to use, or
+// an empty string if there isn't one. If FACTORIO_CONFIG_FILE is set, it
+// must exist. Otherwise, the server data path is searched for one of
+// defaultConfigFileNames.
+func findConfigFile(environ map[string]string, cfg *Config) (string, error) {
+	var configFileEnv, dataPathEnv *field
+	fs := fields(cfg)
+	for i := range fs {
+		switch fs[i].sf.Name {
+		case "ConfigFile":
+			configFileEnv = &fs[i]
+		case "ServerDataPath":
+			dataPathEnv = &fs[i]
+		}
+	}
+
+	if path := environ[configFileEnv.env]; path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("failed to find configuration file set by %s: %w", configFileEnv.env, err)
+		}
+		return path, nil
+	}
+
+	dataPath, ok := environ[dataPathEnv.env]
+	if !ok {
+		dataPath = dataPathEnv.def
+	}
+
+	for _, name := range defaultConfigFileNames {
+		path := filepath.Join(dataPath, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to check for configuration file: %w", err)
+		}
+	}
+
+	return "", nil
+}
+
+// readConfigFile reads a YAML, TOML or JSON configuration file, based on
+// its extension, and returns its contents as environment variables,
+// along with its config.ini overrides.
+func readConfigFile(path string, cfg *Config) (map[string]string, map[string]map[string]string, error) {
+	b, err := os.ReadFile(path) //nolint:gosec // Why: By design.
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var raw map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	case ".json":
+		err = json.Unmarshal(b, &raw)
+	default:
+		return nil, nil, fmt.Errorf("unsupported configuration file extension %q, expected .yaml, .toml or .json", ext)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	environ, ini, err := toEnviron(raw, fields(cfg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return environ, ini, nil
+}
+
+// toEnviron converts the contents of a configuration file into the
+// environment variables for each field, and its config.ini overrides.
+// Keys that don't map to a field are rejected to catch typos.
+func toEnviron(raw map[string]any, fs []field) (map[string]string, map[string]map[string]string, error) {
+	environ := make(map[string]string)
+	var ini map[string]map[string]string
+	byKey := make(map[string]*field, len(fs))
+	for i := range fs {
+		byKey[strings.Join(fs[i].key, ".")] = &fs[i]
+	}
+
+	// Secrets may also be read from a file, e.g., 'token_file'.
+	fileKeys := make(map[string]*field)
+	for i := range fs {
+		if fs[i].secret {
+			fileKeys[strings.Join(fs[i].key, ".")+strings.ToLower(secretFileSuffix)] = &fs[i]
+		}
+	}
+
+	var walk func(m map[string]any, prefix []string) error
+	walk = func(m map[string]any, prefix []string) error {
+		// Sort the keys so that errors are deterministic.
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			key := appendKey(prefix, k)
+			path := strings.Join(key, ".")
+
+			if path == iniFileKey {
+				var err error
+				if ini, err = parseINIFile(m[k]); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if f, ok := byKey[path]; ok {
+				v, err := toEnvValue(m[k], f)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				environ[f.env] = v
+				continue
+			}
+
+			if f, ok := fileKeys[path]; ok {
+				v, ok := m[k].(string)
+				if !ok {
+					return fmt.Errorf("%s: expected a path", path)
+				}
+				environ[f.env+secretFileSuffix] = v
+				continue
+			}
+
+			section, ok := m[k].(map[string]any)
+			if !ok || !isSection(fs, key) {
+				return fmt.Errorf("unknown key %q", path)
+			}
+			if err := walk(section, key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(raw, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return environ, ini, nil
+}
+
+// isSection returns true if key is the prefix of any field's key.
+func isSection(fs []field, key []string) bool {
+	for i := range fs {
+		if len(fs[i].key) > len(key) && strings.Join(fs[i].key[:len(key)], ".") == strings.Join(key, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// toEnvValue converts a value from a configuration file into the string
+// representation used in environment variables.
+func toEnvValue(v any, f *field) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		vals := make([]string, 0, len(v))
+		for _, e := range v {
+			s, err := toEnvValue(e, f)
+			if err != nil {
+				return "", err
+			}
+			if f.sf.Type == argsType {
+				s = shellQuote(s)
+			}
+			vals = append(vals, s)
+		}
+		return strings.Join(vals, f.separator()), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
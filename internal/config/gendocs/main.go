@@ -0,0 +1,175 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package main implements the configuration documentation generator. It
+// extracts the comments of every struct field in the config package and
+// writes them to zz_generated_docs.go, then renders the configuration
+// reference (Markdown and JSON Schema) into the docs directory. It is ran
+// through go generate from the config package's directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// docsDir is the directory, relative to the config package, that the
+// configuration reference is written to.
+const docsDir = "../../docs"
+
+// extractFieldDocs returns the doc comment of every struct field in the
+// Go package in dir, keyed by "<struct>.<field>".
+func extractFieldDocs(dir string) (config.FieldDocs, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(config.FieldDocs)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				ts, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return false
+				}
+
+				for _, field := range st.Fields.List {
+					if field.Doc == nil {
+						continue
+					}
+					for _, name := range field.Names {
+						if !name.IsExported() {
+							continue
+						}
+						docs[ts.Name.Name+"."+name.Name] = strings.TrimSpace(field.Doc.Text())
+					}
+				}
+				return false
+			})
+		}
+	}
+
+	return docs, nil
+}
+
+// renderGoFile returns the contents of zz_generated_docs.go.
+func renderGoFile(header []byte, docs config.FieldDocs) ([]byte, error) {
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.WriteString("// Code generated by go generate ./internal/config. DO NOT EDIT.\n\n")
+	buf.WriteString("package config\n\n")
+	buf.WriteString("var fieldDocs = FieldDocs{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, docs[k])
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// licenseHeader returns the license header at the top of config.go, so
+// that the generated file uses the same one.
+func licenseHeader() ([]byte, error) {
+	b, err := os.ReadFile("config.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		header.Write(line)
+	}
+	header.WriteString("\n")
+
+	return header.Bytes(), nil
+}
+
+// generate writes the generated files.
+func generate() error {
+	docs, err := extractFieldDocs(".")
+	if err != nil {
+		return fmt.Errorf("failed to extract field docs: %w", err)
+	}
+
+	header, err := licenseHeader()
+	if err != nil {
+		return fmt.Errorf("failed to read license header: %w", err)
+	}
+
+	goFile, err := renderGoFile(header, docs)
+	if err != nil {
+		return fmt.Errorf("failed to render Go file: %w", err)
+	}
+
+	schema, err := config.JSONSchema(docs)
+	if err != nil {
+		return fmt.Errorf("failed to render JSON schema: %w", err)
+	}
+
+	if err := os.MkdirAll(docsDir, 0o750); err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"zz_generated_docs.go":                       goFile,
+		filepath.Join(docsDir, "configuration.md"):   config.Markdown(docs),
+		filepath.Join(docsDir, "config.schema.json"): schema,
+	}
+	for path, b := range files {
+		if err := os.WriteFile(path, b, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// main runs the generator.
+func main() {
+	if err := generate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// iniEnvPrefix is the prefix of environment variables that override
+// values in Factorio's config.ini, e.g., FACTORIO_INI__other__port.
+const iniEnvPrefix = envPrefix + "INI__"
+
+// iniFileKey is the key in the configuration file that contains
+// config.ini overrides, keyed by section and then key.
+const iniFileKey = "ini"
+
+// iniSeparator separates the section from the key in environment
+// variables.
+const iniSeparator = "__"
+
+// managedINIKeys are config.ini keys that are always set by the wrapper
+// and can't be overridden.
+var managedINIKeys = map[string]bool{
+	"path.read-data":  true,
+	"path.write-data": true,
+}
+
+// normalizeININame converts a section or key from an environment
+// variable into the name used in config.ini. Environment variables
+// can't contain '-' in most shells, so '_' is accepted in its place.
+func normalizeININame(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// parseINIOverrides returns the config.ini overrides from file, the
+// overrides from the configuration file, with every override in environ
+// applied on top, keyed by section and then key. Sections and keys from
+// the configuration file are used as written, while those from
+// environment variables are normalized with normalizeININame.
+func parseINIOverrides(file map[string]map[string]string,
+	environ map[string]string) (map[string]map[string]string, error) {
+	overrides := make(map[string]map[string]string)
+	set := func(section, key, v string) {
+		if overrides[section] == nil {
+			overrides[section] = make(map[string]string)
+		}
+		overrides[section][key] = v
+	}
+
+	for section, keys := range file {
+		for key, v := range keys {
+			set(section, key, v)
+		}
+	}
+
+	// Sort the environment variables so that which one wins, if several
+	// normalize to the same key, is deterministic.
+	names := make([]string, 0, len(environ))
+	for k := range environ {
+		if strings.HasPrefix(k, iniEnvPrefix) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		section, key, ok := strings.Cut(strings.TrimPrefix(k, iniEnvPrefix), iniSeparator)
+		if !ok || section == "" || key == "" {
+			return nil, fmt.Errorf("invalid config.ini override %s, expected %s<section>%s<key>", k, iniEnvPrefix, iniSeparator)
+		}
+
+		section, key = normalizeININame(section), normalizeININame(key)
+		if managedINIKeys[section+"."+key] {
+			return nil, fmt.Errorf("invalid config.ini override %s: %s.%s is managed by the wrapper", k, section, key)
+		}
+		set(section, key, environ[k])
+	}
+
+	return overrides, nil
+}
+
+// parseINIFile returns the config.ini overrides from a configuration
+// file, keyed by section and then key.
+func parseINIFile(raw any) (map[string]map[string]string, error) {
+	sections, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a section", iniFileKey)
+	}
+
+	overrides := make(map[string]map[string]string, len(sections))
+	for section, keys := range sections {
+		m, ok := keys.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: expected a section", iniFileKey, section)
+		}
+
+		overrides[section] = make(map[string]string, len(m))
+		for key, v := range m {
+			if _, ok := v.([]any); ok {
+				return nil, fmt.Errorf("%s.%s.%s: lists are not supported", iniFileKey, section, key)
+			}
+			if managedINIKeys[section+"."+key] {
+				return nil, fmt.Errorf("%s.%s.%s: managed by the wrapper", iniFileKey, section, key)
+			}
+
+			s, err := toEnvValue(v, &field{})
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s.%s: %w", iniFileKey, section, key, err)
+			}
+			overrides[section][key] = s
+		}
+	}
+
+	return overrides, nil
+}
+
+// INISections returns the sections of config.ini with overrides, sorted.
+func (c *Config) INISections() []string {
+	sections := make([]string, 0, len(c.INI))
+	for s := range c.INI {
+		sections = append(sections, s)
+	}
+	sort.Strings(sections)
+	return sections
+}
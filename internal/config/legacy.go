@@ -0,0 +1,102 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// legacyEnv maps environment variables that have been renamed to their
+// new name. They are still read so that existing deployments keep
+// working.
+var legacyEnv = map[string]string{
+	// FactoCord was replaced by the built-in Discord bridge.
+	"FACTORIO_FACTOCORD_ENABLED":            "FACTORIO_DISCORD_ENABLED",
+	"FACTORIO_FACTOCORD_DISCORD_TOKEN":      "FACTORIO_DISCORD_TOKEN",
+	"FACTORIO_FACTOCORD_DISCORD_TOKEN_FILE": "FACTORIO_DISCORD_TOKEN_FILE",
+	"FACTORIO_FACTOCORD_DISCORD_CHANNEL_ID": "FACTORIO_DISCORD_CHANNEL_ID",
+}
+
+// removedEnv are environment variables that are no longer supported and
+// have no replacement. They're reported so that users know they have no
+// effect.
+var removedEnv = []string{
+	// The Discord bridge doesn't color player names.
+	"FACTORIO_FACTOCORD_DISCORD_USER_COLORS",
+}
+
+// renameLegacyEnv moves every legacy environment variable in environ to
+// its new name, unless the new one is also set. A deprecation warning is
+// returned for each legacy or removed environment variable found.
+func renameLegacyEnv(environ map[string]string, fs []field) []string {
+	secrets := make(map[string]bool)
+	for i := range fs {
+		if fs[i].secret {
+			secrets[fs[i].env] = true
+		}
+	}
+
+	names := make([]string, 0, len(legacyEnv))
+	for old := range legacyEnv {
+		names = append(names, old)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, old := range names {
+		v, ok := environ[old]
+		if !ok {
+			continue
+		}
+
+		newName := legacyEnv[old]
+		delete(environ, old)
+
+		// Secrets are removed from the environment once read, like the
+		// new environment variable would be, so that they aren't passed
+		// on to Factorio.
+		if secrets[newName] {
+			os.Unsetenv(old) //nolint:errcheck // Why: Best effort.
+		}
+
+		if _, ok := environ[newName]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated and ignored because %s is set", old, newName))
+			continue
+		}
+
+		environ[newName] = v
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated, use %s instead", old, newName))
+	}
+
+	for _, name := range removedEnv {
+		if _, ok := environ[name]; ok {
+			delete(environ, name)
+			warnings = append(warnings, fmt.Sprintf("%s is no longer supported and is ignored", name))
+		}
+	}
+
+	return warnings
+}
+
+// Deprecations returns a warning for every deprecated setting used by
+// the configuration.
+func (c *Config) Deprecations() []string {
+	return c.deprecations
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted is displayed in place of secrets.
+const redacted = "<redacted>"
+
+// MarshalRedacted returns the configuration as YAML, in the same format
+// accepted by configuration files, with all secrets redacted.
+func (c *Config) MarshalRedacted() ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range fields(c) {
+		// Lists that weren't provided are omitted, since an empty list
+		// is not the same as no list.
+		if f.v.Kind() == reflect.Slice && f.v.IsNil() {
+			continue
+		}
+
+		// Find or create the section for the field.
+		section := root
+		for _, k := range f.key[:len(f.key)-1] {
+			section = mappingValue(section, k)
+		}
+
+		var v any = f.v.Interface()
+		if d, ok := v.(time.Duration); ok {
+			v = d.String()
+		}
+		if f.secret && !f.v.IsZero() {
+			v = redacted
+		}
+
+		var node yaml.Node
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: f.key[len(f.key)-1]}, &node)
+	}
+
+	if len(c.INI) > 0 {
+		ini := mappingValue(root, iniFileKey)
+		for _, section := range c.INISections() {
+			var node yaml.Node
+			if err := node.Encode(c.INI[section]); err != nil {
+				return nil, err
+			}
+			ini.Content = append(ini.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: section}, &node)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the mapping stored under key in the mapping node
+// m, creating it if it doesn't exist.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	v := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretFileSuffix is appended to the environment variable of a secret
+// to get the environment variable containing the path to a file to read
+// it from, following the Docker and Kubernetes secrets convention.
+const secretFileSuffix = "_FILE"
+
+// readSecretFile reads a secret from the file at path, stripping the
+// trailing newline most editors and tools add.
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path) //nolint:gosec // Why: By design.
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// resolveSecretFiles reads every secret that was provided as a file
+// (e.g., FACTORIO_TOKEN_FILE) into environ, returning the path of each
+// file keyed by the environment variable of the secret.
+func resolveSecretFiles(environ map[string]string, fs []field) (map[string]string, error) {
+	files := make(map[string]string)
+	for i := range fs {
+		f := &fs[i]
+		if !f.secret {
+			continue
+		}
+
+		path := environ[f.env+secretFileSuffix]
+		if path == "" {
+			continue
+		}
+
+		if environ[f.env] != "" {
+			return nil, fmt.Errorf("only one of %s and %s%s may be set", f.env, f.env, secretFileSuffix)
+		}
+
+		secret, err := readSecretFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s%s: %w", f.env, secretFileSuffix, err)
+		}
+
+		environ[f.env] = secret
+		files[f.env] = path
+	}
+
+	return files, nil
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// Validate checks the configuration for problems that would otherwise
+// only surface once the server is being installed or ran. Every problem
+// found is returned, joined together, and is prefixed with the name of
+// the environment variable that caused it. Validate does not use the
+// network, but does check that directories are writable.
+func (c *Config) Validate() error {
+	v := &validator{cfg: c}
+
+	if !isChannel(c.Version) {
+		if _, err := factorio.ParseVersion(c.Version); err != nil {
+			if _, err := factorio.ParseConstraint(c.Version); err != nil {
+				v.addf(&c.Version, "%q is not 'stable', 'experimental', a version (e.g., 2.0.28) or a constraint (e.g., ~2.0)",
+					c.Version)
+			}
+		}
+	}
+
+	switch c.VerifyInstall {
+	case "off", "fast", "full":
+	default:
+		v.addf(&c.VerifyInstall, "%q is not one of 'off', 'fast' or 'full'", c.VerifyInstall)
+	}
+
+	if c.RollbackTimeout < 0 {
+		v.addf(&c.RollbackTimeout, "must not be negative")
+	}
+
+	switch c.Players.WhitelistMode {
+	case "off", "on", "auto":
+	default:
+		v.addf(&c.Players.WhitelistMode, "%q is not one of 'off', 'on' or 'auto'", c.Players.WhitelistMode)
+	}
+
+	if c.Players.SyncSource != "" && c.Players.SyncInterval <= 0 {
+		v.addf(&c.Players.SyncInterval, "must be positive when %s is set", v.env(&c.Players.SyncSource))
+	}
+
+	if c.AutoUpgrade.Enabled {
+		if _, err := factorio.ParseVersion(c.Version); err == nil {
+			v.addf(&c.AutoUpgrade.Enabled, "requires %s to be a channel or constraint, not a version", v.env(&c.Version))
+		}
+		if c.AutoUpgrade.Interval <= 0 {
+			v.addf(&c.AutoUpgrade.Interval, "must be positive when %s is true", v.env(&c.AutoUpgrade.Enabled))
+		}
+	}
+	switch c.AutoUpgrade.Mode {
+	case "wait", "countdown":
+	default:
+		v.addf(&c.AutoUpgrade.Mode, "%q is not one of 'wait' or 'countdown'", c.AutoUpgrade.Mode)
+	}
+	if c.AutoUpgrade.Countdown < 0 {
+		v.addf(&c.AutoUpgrade.Countdown, "must not be negative")
+	}
+
+	if c.Token != "" && c.Username == "" {
+		v.addf(&c.Username, "must be set when %s is set", v.env(&c.Token))
+	}
+
+	if c.Discord.Enabled {
+		if c.Discord.Token == "" {
+			v.addf(&c.Discord.Token, "must be set when %s is true", v.env(&c.Discord.Enabled))
+		}
+		if c.Discord.ChannelID == "" {
+			v.addf(&c.Discord.ChannelID, "must be set when %s is true", v.env(&c.Discord.Enabled))
+		}
+	}
+
+	for _, port := range []*int{&c.Port, &c.RCONPort} {
+		if *port < 1 || *port > 65535 {
+			v.addf(port, "%d is not a valid port", *port)
+		}
+	}
+	for _, addr := range []*string{&c.BindAddress, &c.RCONBind} {
+		if *addr != "" && net.ParseIP(*addr) == nil {
+			v.addf(addr, "%q is not an IP address", *addr)
+		}
+	}
+
+	for _, arg := range c.ExtraArgs {
+		if flag, ok := c.managedFlag(arg); ok {
+			v.addf(&c.ExtraArgs, "%s is set by the wrapper and can't be passed as an extra argument", flag)
+		}
+	}
+
+	switch c.LogFormat {
+	case "text", "json", "logfmt":
+	default:
+		v.addf(&c.LogFormat, "%q is not one of 'text', 'json' or 'logfmt'", c.LogFormat)
+	}
+	if _, err := c.SlogLevel(); err != nil {
+		v.addf(&c.LogLevel, "%q is not one of 'debug', 'info', 'warn' or 'error'", c.LogLevel)
+	}
+
+	if c.Logs.MaxSize < 1 {
+		v.addf(&c.Logs.MaxSize, "must be at least 1")
+	}
+	if c.Logs.MaxAge < 0 {
+		v.addf(&c.Logs.MaxAge, "must not be negative")
+	}
+	if c.Logs.MaxBackups < 0 {
+		v.addf(&c.Logs.MaxBackups, "must not be negative")
+	}
+
+	for _, u := range c.Webhooks.URLs {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			v.addf(&c.Webhooks.URLs, "%q is not an HTTP(S) URL", u)
+		}
+	}
+	if c.Webhooks.Retries < 0 {
+		v.addf(&c.Webhooks.Retries, "must not be negative")
+	}
+	if len(c.Webhooks.URLs) > 0 && c.Webhooks.Timeout <= 0 {
+		v.addf(&c.Webhooks.Timeout, "must be positive when %s is set", v.env(&c.Webhooks.URLs))
+	}
+
+	v.checkWritableDir(&c.InstallPath)
+	v.checkWritableDir(&c.ServerDataPath)
+
+	return errors.Join(v.errs...)
+}
+
+// isChannel returns true if version is the name of a release channel.
+func isChannel(version string) bool {
+	return version == "stable" || version == "experimental"
+}
+
+// validator collects problems found while validating a Config.
+type validator struct {
+	cfg  *Config
+	errs []error
+}
+
+// env returns the environment variable for the field ptr points to.
+func (v *validator) env(ptr any) string {
+	return v.cfg.EnvName(ptr)
+}
+
+// EnvName returns the environment variable used to configure the field
+// of c that ptr points to, e.g., EnvName(&c.Port) returns FACTORIO_PORT.
+// It panics if ptr doesn't point to a configurable field of c.
+func (c *Config) EnvName(ptr any) string {
+	addr := reflect.ValueOf(ptr).Pointer()
+	for _, f := range fields(c) {
+		if f.v.Addr().Pointer() == addr {
+			return f.env
+		}
+	}
+
+	panic("config: no environment variable for field")
+}
+
+// addf records a problem with the field ptr points to.
+func (v *validator) addf(ptr any, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", v.env(ptr), fmt.Sprintf(format, args...)))
+}
+
+// checkWritableDir ensures that the directory at *path, or the closest
+// parent directory that exists if it will be created, is writable.
+func (v *validator) checkWritableDir(path *string) {
+	if *path == "" {
+		v.addf(path, "must be set")
+		return
+	}
+
+	dir := filepath.Clean(*path)
+	for {
+		inf, err := os.Stat(dir)
+		if err == nil {
+			if !inf.IsDir() {
+				v.addf(path, "%s is not a directory", dir)
+				return
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			v.addf(path, "failed to check %s: %v", dir, err)
+			return
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	// The only reliable way to check if we can write to a directory is
+	// to try it.
+	f, err := os.CreateTemp(dir, ".wrapper-write-test-*")
+	if err != nil {
+		v.addf(path, "%s is not writable: %v", dir, err)
+		return
+	}
+	f.Close()           //nolint:errcheck // Why: Best effort.
+	os.Remove(f.Name()) //nolint:errcheck // Why: Best effort.
+}
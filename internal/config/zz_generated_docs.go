@@ -0,0 +1,72 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Code generated by go generate ./internal/config. DO NOT EDIT.
+
+package config
+
+var fieldDocs = FieldDocs{
+	"AutoUpgrade.Countdown":  "Countdown is how long players are warned for before the server is\nupgraded, when Mode is 'countdown'.",
+	"AutoUpgrade.Enabled":    "Enabled determines whether to check for new versions of Factorio\nwhile the server is running. If a newer version is found, the\nserver is saved, stopped, upgraded and started again. Requires\nVersion to be a channel or constraint.",
+	"AutoUpgrade.Interval":   "Interval is how often to check for a new version.",
+	"AutoUpgrade.Mode":       "Mode controls what happens when players are online once a new\nversion is found. If set to 'wait', the upgrade waits until no\nplayers are online. If set to 'countdown', players are warned and\nthe server is upgraded once Countdown has passed.",
+	"Config.AllowDowngrade":  "AllowDowngrade allows installing a version of Factorio older than\nthe one the save loaded by the server was made with. Older\nversions can't load saves made by newer ones, so this is refused\nby default.",
+	"Config.AutoUpgrade":     "AutoUpgrade is configuration for upgrading Factorio while the\nserver is running.",
+	"Config.BindAddress":     "BindAddress is the IP address the game listens on. If not set, it\nlistens on every interface.",
+	"Config.ConfigFile":      "ConfigFile is the path to a YAML, TOML or JSON configuration file.\nIf unset, wrapper.yaml (or .yml, .toml, .json) in ServerDataPath is\nused if it exists. Values in the file take precedence over\ndefaults, while environment variables take precedence over the\nfile. Keys are the lowercase environment variable names without the\nFACTORIO_ prefix, e.g., 'install_path', with prefixed groups as\nnested sections, e.g., 'discord.enabled'.",
+	"Config.Discord":         "Discord is configuration for the Discord bridge.",
+	"Config.ExtraArgs":       "ExtraArgs are extra command line arguments passed to Factorio,\nafter the ones set by the wrapper. In the environment variable,\nthey are parsed using shell quoting rules (e.g., '--mod-directory\n\"/data/my mods\"'), while the configuration file accepts a list.\nFlags set by the wrapper, such as --server-settings or --port, are\nrejected, as is --console-log unless Logs.Enabled is false.",
+	"Config.GamePassword":    "GamePassword is the password required to join the server. If set,\nit overrides the password in server-settings.json.",
+	"Config.INI":             "INI contains overrides for Factorio's config.ini, keyed by section\nand then key. They're set through FACTORIO_INI__<section>__<key>\nenvironment variables or the 'ini' section of the configuration\nfile, and applied on every start. Environment variables take\nprecedence over the configuration file.",
+	"Config.INIReset":        "INIReset determines whether config.ini is reset to Factorio's\ndefaults on every start, before INI overrides are applied. This\ndiscards any changes made to it by hand.",
+	"Config.InstallPath":     "InstallPath is the location to install Factorio to. This is NOT\nwhere the save files are stored.",
+	"Config.LogFormat":       "LogFormat is the format of the wrapper's own log output, which\nalso includes the output of the server. One of 'text' (human\nreadable), 'json' or 'logfmt'.",
+	"Config.LogLevel":        "LogLevel is the minimum level of log messages to output. One of\n'debug', 'info', 'warn' or 'error'.",
+	"Config.Logs":            "Logs is configuration for the server logs kept in ServerDataPath.",
+	"Config.Players":         "Players declares the admins, whitelisted and banned players of the\nserver.",
+	"Config.Port":            "Port is the UDP port the game listens on.",
+	"Config.RCONBind":        "RCONBind is the IP address RCON listens on. If not set, RCON\nlistens on every interface when RCONPassword is set, and only on\nlocalhost otherwise, where it is used by the wrapper with a random\npassword.",
+	"Config.RCONPassword":    "RCONPassword is the password for the RCON interface. If set, RCON\ncan be used from outside of the container.",
+	"Config.RCONPort":        "RCONPort is the TCP port RCON listens on.",
+	"Config.RollbackTimeout": "RollbackTimeout is how long the server has to start after Factorio\nis upgraded. If it exits or hasn't started in time, the previous\nversion and the save from before the upgrade are restored, and the\nnew version is skipped until a newer one is released. If set to\n'0', upgrades are not rolled back.",
+	"Config.ServerDataPath":  "ServerDataPath is the location to store server data, such as\nsave files.",
+	"Config.Token":           "Token is the factorio.com token for the server. This is only\nrequired for making a server public. For more information, see:\nhttps://wiki.factorio.com/Multiplayer#How_to_list_a_server-hosted_game_on_the_matching_server",
+	"Config.Username":        "Username is the factorio.com username for who owns this server.\nThis is only required for making a server public. For more\ninformation, see:\nhttps://wiki.factorio.com/Multiplayer#How_to_list_a_server-hosted_game_on_the_matching_server",
+	"Config.VerifyInstall":   "VerifyInstall controls how the installed Factorio server is checked\nfor corruption on startup. If set to 'fast', file sizes and\nmodification times are compared. If set to 'full', every file is\nhashed. If set to 'off', no checks are done. If a check fails,\nFactorio is reinstalled.",
+	"Config.Version":         "Version is the desired version of Factorio to run. If set to\n'stable' or 'experimental', the latest version of that channel will\nbe used. Note that this means it will also be updated on every\nrestart. If set to a specific version, that version will be used.\nIf set to a constraint (e.g., '~2.0' or '>=1.1.100 <2.0'), the\nnewest published release satisfying it will be used.",
+	"Config.Webhooks":        "Webhooks is configuration for sending server events to webhooks.",
+	"Discord.AdminRoleIDs":   "AdminRoleIDs is a comma separated list of IDs of Discord roles\nallowed to run admin commands (e.g., '!kick') in the channel.",
+	"Discord.ChannelID":      "ChannelID is the ID of the Discord channel to relay chat to and\nfrom.",
+	"Discord.Enabled":        "Enabled determines whether the Discord bridge is enabled.",
+	"Discord.Token":          "Token is the Bot user token to use for the Discord API. The bot\nneeds the Message Content intent.",
+	"Logs.Enabled":           "Enabled determines whether the output of the server and its\nconsole log are written to the 'logs' directory.",
+	"Logs.MaxAge":            "MaxAge is how long to keep rotated logs for, e.g., '336h'. It is\nrounded up to whole days. If set to '0', they're kept regardless\nof age.",
+	"Logs.MaxBackups":        "MaxBackups is how many rotated logs of each kind to keep. If set\nto 0, they're kept regardless of count.",
+	"Logs.MaxSize":           "MaxSize is the size in megabytes at which the server output log is\nrotated. Rotated logs are compressed with gzip.",
+	"Players.Admins":         "Admins is a comma separated list of players that are server\nadmins.",
+	"Players.Bans":           "Bans is a semicolon separated list of banned players, in the format\n'username' or 'username:reason'.",
+	"Players.SyncInterval":   "SyncInterval is how often to read player lists from SyncSource,\ne.g., '5m' or '1h'.",
+	"Players.SyncSource":     "SyncSource is an HTTP(S) URL or the path to a JSON file to\nperiodically read player lists from. It must contain an object\nwith the optional keys 'admins', 'whitelist' and 'bans', in the\nsame format as Factorio's player list files. Lists in the source\ntake precedence over the ones set in the configuration.",
+	"Players.Whitelist":      "Whitelist is a comma separated list of players that are allowed to\njoin the server.",
+	"Players.WhitelistMode":  "WhitelistMode controls whether only whitelisted players can join\nthe server. If set to 'on', the whitelist is always enforced. If\nset to 'off', anyone can join. If set to 'auto', the whitelist is\nonly enforced when it contains at least one player.",
+	"Webhooks.ContentType":   "ContentType is the Content-Type of the webhook requests.",
+	"Webhooks.Retries":       "Retries is how many times to retry sending an event that failed\nwith a network or server error.",
+	"Webhooks.Secret":        "Secret is used to sign the body of every request with\nHMAC-SHA256. The signature is sent in the X-Signature-256 header\nas 'sha256=<hex>'.",
+	"Webhooks.TemplateDir":   "TemplateDir is a directory containing a Go template for the body\nof each event, named '<event>.tmpl' (e.g., 'player_joined.tmpl').\nEvents without a template are sent as JSON. Supported events are\nserver_started, server_stopped, server_crashed, version_upgraded,\nversion_downgraded, version_rolled_back, player_joined, player_left,\nbackup_succeeded and backup_failed.",
+	"Webhooks.Timeout":       "Timeout is how long to wait for a webhook to respond.",
+	"Webhooks.URLs":          "URLs is a comma separated list of URLs to POST events to. If not\nset, no webhooks are sent.",
+}
@@ -0,0 +1,301 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// commandPrefix is the prefix of admin commands sent in Discord.
+const commandPrefix = "!"
+
+// outboxSize is how many messages to Discord are buffered before new
+// ones are dropped.
+const outboxSize = 100
+
+// Commander runs commands on a Factorio server.
+type Commander interface {
+	// RCON runs a command on the server and returns its output.
+	RCON(ctx context.Context, cmd string) (string, error)
+}
+
+// Bridge relays chat between a Factorio server and a Discord channel,
+// posts notices when players join or leave and when the server starts or
+// stops, and allows admins to run commands.
+type Bridge struct {
+	log *slog.Logger
+	cfg config.Discord
+	c   *client
+
+	// outbox contains messages waiting to be sent to Discord, so that
+	// console output is never blocked on Discord.
+	outbox chan string
+
+	mu  sync.Mutex
+	srv Commander
+}
+
+// New returns a new Bridge for the provided configuration. The bridge
+// does nothing until Run is called.
+func New(log *slog.Logger, cfg config.Discord) *Bridge {
+	return &Bridge{
+		log:    log,
+		cfg:    cfg,
+		c:      &client{token: cfg.Token, http: &http.Client{Timeout: 30 * time.Second}},
+		outbox: make(chan string, outboxSize),
+	}
+}
+
+// SetServer sets the server that messages and commands from Discord are
+// sent to. Until it is set, they are ignored.
+func (b *Bridge) SetServer(srv Commander) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.srv = srv
+}
+
+// SetToken changes the bot token, e.g., once it has been rotated. It is
+// used for every request from now on, and for the gateway the next time
+// it reconnects.
+func (b *Bridge) SetToken(token string) {
+	b.c.setToken(token)
+}
+
+// server returns the server set by SetServer, if any.
+func (b *Bridge) server() Commander {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.srv
+}
+
+// Run connects to Discord and relays messages until the context is
+// canceled, reconnecting whenever the connection is lost.
+func (b *Bridge) Run(ctx context.Context) {
+	go b.send(ctx)
+
+	const maxBackoff = time.Minute
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := b.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Reset the backoff if the connection was healthy for a while.
+		if time.Since(start) > maxBackoff {
+			backoff = time.Second
+		}
+
+		if errors.Is(err, errReconnect) {
+			b.log.Debug("Reconnecting to Discord", "reason", err)
+		} else {
+			b.log.Warn("Lost connection to Discord, reconnecting", "err", err, "backoff", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// connect connects to the gateway once, handling messages until the
+// connection fails.
+func (b *Bridge) connect(ctx context.Context) error {
+	url, err := b.c.gatewayURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	return connectGateway(ctx, url, b.c.getToken(), func(msg *Message) {
+		b.handleMessage(ctx, msg)
+	})
+}
+
+// send sends queued messages to Discord until the context is canceled.
+func (b *Bridge) send(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case content := <-b.outbox:
+			if err := b.c.sendMessage(ctx, b.cfg.ChannelID, content); err != nil {
+				b.log.Warn("Failed to send message to Discord", "err", err)
+			}
+		}
+	}
+}
+
+// post queues a message to be sent to the Discord channel.
+func (b *Bridge) post(content string) {
+	select {
+	case b.outbox <- content:
+	default:
+		b.log.Warn("Too many messages queued for Discord, dropping message")
+	}
+}
+
+// HandleConsoleEvent relays an event from the server console to Discord.
+func (b *Bridge) HandleConsoleEvent(ev factorio.ConsoleEvent) {
+	// Messages sent by the server include the ones we relay from
+	// Discord, so they must not be sent back.
+	if ev.Player == factorio.ServerPlayer {
+		return
+	}
+
+	switch ev.Type {
+	case factorio.ConsoleEventChat:
+		b.post(fmt.Sprintf("**%s**: %s", escapeMarkdown(ev.Player), escapeMarkdown(ev.Message)))
+	case factorio.ConsoleEventJoin:
+		b.post(fmt.Sprintf("**%s** joined the game", escapeMarkdown(ev.Player)))
+	case factorio.ConsoleEventLeave:
+		b.post(fmt.Sprintf("**%s** left the game", escapeMarkdown(ev.Player)))
+	}
+}
+
+// NotifyStarted posts a notice that the server has started.
+func (b *Bridge) NotifyStarted() {
+	b.post("Server started")
+}
+
+// NotifyStopped sends a notice that the server has stopped. Unlike other
+// messages, it is sent immediately since the wrapper is likely exiting.
+func (b *Bridge) NotifyStopped(ctx context.Context, err error) {
+	content := "Server stopped"
+	if err != nil {
+		content = "Server crashed"
+	}
+
+	if err := b.c.sendMessage(ctx, b.cfg.ChannelID, content); err != nil {
+		b.log.Warn("Failed to send message to Discord", "err", err)
+	}
+}
+
+// handleMessage handles a message sent in Discord.
+func (b *Bridge) handleMessage(ctx context.Context, msg *Message) {
+	if msg.ChannelID != b.cfg.ChannelID || msg.Author.Bot {
+		return
+	}
+
+	content := strings.Join(strings.Fields(msg.Content), " ")
+	if content == "" {
+		return
+	}
+
+	srv := b.server()
+	if srv == nil {
+		return
+	}
+
+	if strings.HasPrefix(content, commandPrefix) {
+		b.post(b.runCommand(ctx, srv, msg, strings.TrimPrefix(content, commandPrefix)))
+		return
+	}
+
+	// The prefix also ensures that the message can't be interpreted as a
+	// command by the server.
+	chat := fmt.Sprintf("[Discord] %s: %s", msg.DisplayName(), content)
+	if _, err := srv.RCON(ctx, chat); err != nil {
+		b.log.Warn("Failed to relay message from Discord", "err", err)
+	}
+}
+
+// isAdmin returns true if the author of msg has an admin role.
+func (b *Bridge) isAdmin(msg *Message) bool {
+	if msg.Member == nil {
+		return false
+	}
+	return slices.ContainsFunc(msg.Member.Roles, func(r string) bool {
+		return slices.Contains(b.cfg.AdminRoleIDs, r)
+	})
+}
+
+// helpText describes the supported admin commands.
+const helpText = "Commands: `!players`, `!save`, `!kick <player> [reason]`, " +
+	"`!ban <player> [reason]`, `!unban <player>`"
+
+// runCommand runs an admin command, returning the reply to send.
+func (b *Bridge) runCommand(ctx context.Context, srv Commander, msg *Message, content string) string {
+	if !b.isAdmin(msg) {
+		return "You are not allowed to run commands."
+	}
+
+	args := strings.Fields(content)
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	player := ""
+	if len(args) > 1 {
+		player = args[1]
+	}
+	reason := strings.Join(args[min(len(args), 2):], " ")
+
+	var cmd string
+	switch {
+	case name == "players" && len(args) == 1:
+		cmd = "/players online"
+	case name == "save" && len(args) == 1:
+		cmd = "/server-save"
+	case name == "kick" && player != "":
+		cmd = strings.TrimSpace("/kick " + player + " " + reason)
+	case name == "ban" && player != "":
+		cmd = strings.TrimSpace("/ban " + player + " " + reason)
+	case name == "unban" && len(args) == 2:
+		cmd = "/unban " + player
+	default:
+		return helpText
+	}
+
+	b.log.Info("Running command from Discord", "user", msg.Author.Username, "command", cmd)
+	out, err := srv.RCON(ctx, cmd)
+	if err != nil {
+		b.log.Warn("Failed to run command from Discord", "err", err)
+		return "Failed to run command."
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "Done."
+	}
+	return "```\n" + strings.ReplaceAll(out, "```", "'''") + "\n```"
+}
+
+// markdownReplacer escapes characters that Discord treats as Markdown.
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`,
+)
+
+// escapeMarkdown escapes s so that it is displayed as-is in Discord.
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package discord_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/discord"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+// fakeServer records the commands sent to it.
+type fakeServer struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (s *fakeServer) RCON(_ context.Context, cmd string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = append(s.commands, cmd)
+	return "", nil
+}
+
+func (s *fakeServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.commands)
+}
+
+// fakeDiscord is a fake Discord API and gateway that sends the provided
+// messages once a client identifies, and records messages posted to
+// channels.
+func fakeDiscord(t *testing.T, messages []discord.Message) (*httptest.Server, <-chan string) {
+	posted := make(chan string, 100)
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("GET /gateway/bot", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{ //nolint:errcheck // Why: Test.
+			"url": "ws" + strings.TrimPrefix(srv.URL, "http") + "/gateway",
+		})
+	})
+	mux.HandleFunc("POST /channels/1234/messages", func(w http.ResponseWriter, r *http.Request) {
+		assert.Check(t, r.Header.Get("Authorization") == "Bot secret")
+
+		var msg struct {
+			Content string `json:"content"`
+		}
+		assert.Check(t, json.NewDecoder(r.Body).Decode(&msg))
+		posted <- msg.Content
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gateway/", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow() //nolint:errcheck // Why: Test.
+
+		ctx := r.Context()
+		if wsjson.Write(ctx, conn, map[string]any{"op": 10, "d": map[string]any{"heartbeat_interval": 45000}}) != nil {
+			return
+		}
+
+		var identify struct {
+			Op int `json:"op"`
+			D  struct {
+				Token string `json:"token"`
+			} `json:"d"`
+		}
+		if wsjson.Read(ctx, conn, &identify) != nil {
+			return
+		}
+		assert.Check(t, identify.Op == 2 && identify.D.Token == "secret")
+
+		for i, msg := range messages {
+			err := wsjson.Write(ctx, conn, map[string]any{"op": 0, "s": i + 1, "t": "MESSAGE_CREATE", "d": msg})
+			if err != nil {
+				return
+			}
+		}
+
+		// Wait for the client to disconnect.
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Cleanup(discord.SetAPIURL(srv.URL))
+	return srv, posted
+}
+
+// waitForPosts waits for n messages to be posted.
+func waitForPosts(t *testing.T, posted <-chan string, n int) []string {
+	var out []string
+	timeout := time.After(5 * time.Second)
+	for len(out) < n {
+		select {
+		case p := <-posted:
+			out = append(out, p)
+		case <-timeout:
+			t.Fatalf("timed out waiting for messages, got %q", out)
+		}
+	}
+	return out
+}
+
+func TestBridgeRelaysMessages(t *testing.T) {
+	admin := &discord.Member{Roles: []string{"42"}}
+	_, posted := fakeDiscord(t, []discord.Message{
+		{ChannelID: "1234", Content: "hello\nthere", Author: discord.Author{Username: "alice"}},
+		{ChannelID: "1234", Content: "ignored", Author: discord.Author{Username: "bot", Bot: true}},
+		{ChannelID: "5678", Content: "other channel", Author: discord.Author{Username: "alice"}},
+		{ChannelID: "1234", Content: "!save", Author: discord.Author{Username: "bob"}},
+		{ChannelID: "1234", Content: "!kick griefer being rude", Author: discord.Author{Username: "carol"}, Member: admin},
+	})
+
+	srv := &fakeServer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := discord.New(slog.New(slog.NewTextHandler(io.Discard, nil)), config.Discord{
+		Token: "secret", ChannelID: "1234", AdminRoleIDs: []string{"42"},
+	})
+	b.SetServer(srv)
+	go b.Run(ctx)
+
+	replies := waitForPosts(t, posted, 2)
+	assert.DeepEqual(t, replies, []string{"You are not allowed to run commands.", "Done."})
+	assert.DeepEqual(t, srv.Commands(), []string{"[Discord] alice: hello there", "/kick griefer being rude"})
+
+	// Events from the game are relayed to Discord, except for messages
+	// from the server itself.
+	b.HandleConsoleEvent(factorio.ConsoleEvent{Type: factorio.ConsoleEventChat, Player: factorio.ServerPlayer, Message: "echo"})
+	b.HandleConsoleEvent(factorio.ConsoleEvent{Type: factorio.ConsoleEventChat, Player: "dave", Message: "*hi*"})
+	b.HandleConsoleEvent(factorio.ConsoleEvent{Type: factorio.ConsoleEventJoin, Player: "erin"})
+	assert.DeepEqual(t, waitForPosts(t, posted, 2), []string{`**dave**: \*hi\*`, "**erin** joined the game"})
+}
+
+func TestRateLimitedMessagesAreRetried(t *testing.T) {
+	var mu sync.Mutex
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Content string `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&msg) //nolint:errcheck // Why: Test.
+
+		mu.Lock()
+		defer mu.Unlock()
+		posted = append(posted, msg.Content)
+		if len(posted) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.05}`)) //nolint:errcheck // Why: Test.
+		}
+	}))
+	defer srv.Close()
+	defer discord.SetAPIURL(srv.URL)()
+
+	b := discord.New(slog.New(slog.NewTextHandler(io.Discard, nil)), config.Discord{Token: "secret", ChannelID: "1234"})
+	start := time.Now()
+	b.NotifyStopped(context.Background(), nil)
+	assert.Assert(t, time.Since(start) >= 50*time.Millisecond, "expected to wait for the rate limit to reset")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.DeepEqual(t, posted, []string{"Server stopped", "Server stopped"})
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package discord implements a bridge between a Factorio server and a
+// Discord channel, relaying chat both ways and allowing admins to run a
+// small set of commands on the server.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiURL is the base URL of the Discord REST API.
+var apiURL = "https://discord.com/api/v10"
+
+// client is a minimal client for the Discord REST API.
+type client struct {
+	http *http.Client
+
+	// mu protects token, which can be changed while requests are made.
+	mu    sync.Mutex
+	token string
+}
+
+// setToken changes the token used to authenticate requests.
+func (c *client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// getToken returns the token used to authenticate requests.
+func (c *client) getToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// maxRateLimitRetries is how many times a rate limited request is
+// retried before giving up.
+const maxRateLimitRetries = 3
+
+// maxRetryAfter is the longest the client waits for a rate limit to
+// reset. Longer rate limits fail the request instead.
+const maxRetryAfter = time.Minute
+
+// do sends a request to the Discord API, decoding the response into out
+// if it is not nil. Rate limited requests are retried once the rate
+// limit resets.
+func (c *client) do(ctx context.Context, method, path string, in, out any) error {
+	var b []byte
+	if in != nil {
+		var err error
+		if b, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.request(ctx, method, path, b)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			defer resp.Body.Close() //nolint:errcheck // Why: Best effort.
+			if out == nil {
+				return nil
+			}
+			return json.NewDecoder(resp.Body).Decode(out)
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:errcheck // Why: Only used for the error.
+		resp.Body.Close()                                      //nolint:errcheck // Why: Best effort.
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			if wait, ok := retryAfter(body); ok {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+				continue
+			}
+		}
+
+		return fmt.Errorf("%s %s: unexpected status code %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(body))
+	}
+}
+
+// request sends a single request to the Discord API with the JSON body b,
+// if it is not nil.
+func (c *client) request(ctx context.Context, method, path string, b []byte) (*http.Response, error) {
+	var body io.Reader = http.NoBody
+	if b != nil {
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bot "+c.getToken())
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/jaredallard/factorio-docker, 1)")
+	if b != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.http.Do(req)
+}
+
+// retryAfter returns how long to wait before retrying a rate limited
+// request, from the retry_after field, in seconds, of the response body.
+// It returns false if the wait is unknown or too long.
+func retryAfter(body []byte) (time.Duration, bool) {
+	var resp struct {
+		RetryAfter *float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.RetryAfter == nil {
+		return 0, false
+	}
+
+	wait := time.Duration(*resp.RetryAfter * float64(time.Second))
+	if wait < 0 || wait > maxRetryAfter {
+		return 0, false
+	}
+	return wait, true
+}
+
+// allowedMentions controls which mentions in a message notify users.
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// message is a message to send to a channel.
+type message struct {
+	Content         string          `json:"content"`
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+// sendMessage sends a message to a channel. Mentions in the message
+// never notify anyone, since they may come from players.
+func (c *client) sendMessage(ctx context.Context, channelID, content string) error {
+	msg := message{Content: content, AllowedMentions: allowedMentions{Parse: []string{}}}
+	if err := c.do(ctx, http.MethodPost, "/channels/"+channelID+"/messages", msg, nil); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// gatewayURL returns the URL to connect to the gateway with.
+func (c *client) gatewayURL(ctx context.Context) (string, error) {
+	var resp struct {
+		URL string `json:"url"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/gateway/bot", nil, &resp); err != nil {
+		return "", fmt.Errorf("failed to get gateway URL: %w", err)
+	}
+
+	return resp.URL + "/?v=10&encoding=json", nil
+}
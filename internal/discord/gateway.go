@@ -0,0 +1,223 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// Gateway opcodes used by the bridge. For more information, see:
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-opcodes
+const (
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatAck   = 11
+)
+
+// intents are the gateway intents the bridge needs: guild messages and
+// their contents.
+const intents = 1<<9 | 1<<15
+
+// errReconnect is returned when the gateway asks us to reconnect.
+var errReconnect = errors.New("gateway requested a reconnect")
+
+// payload is a message sent or received over the gateway.
+type payload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d,omitempty"`
+	S  *int64          `json:"s,omitempty"`
+	T  string          `json:"t,omitempty"`
+}
+
+// Author is the author of a Discord message.
+type Author struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+// Member is the guild member that sent a Discord message.
+type Member struct {
+	Nick  string   `json:"nick"`
+	Roles []string `json:"roles"`
+}
+
+// Message is a message sent to a Discord channel.
+type Message struct {
+	ChannelID string  `json:"channel_id"`
+	Content   string  `json:"content"`
+	Author    Author  `json:"author"`
+	Member    *Member `json:"member"`
+}
+
+// DisplayName returns the name to show for the author of the message.
+func (m *Message) DisplayName() string {
+	if m.Member != nil && m.Member.Nick != "" {
+		return m.Member.Nick
+	}
+	return m.Author.Username
+}
+
+// gateway is a single connection to the Discord gateway.
+type gateway struct {
+	conn *websocket.Conn
+
+	// seq is the last sequence number received, which is sent with
+	// heartbeats.
+	seq atomic.Pointer[int64]
+}
+
+// connectGateway connects to the gateway at url and identifies with
+// token. Messages created in any channel the bot can see are passed to
+// onMessage until the connection fails or the context is canceled.
+func connectGateway(ctx context.Context, url, token string, onMessage func(*Message)) error {
+	conn, _, err := websocket.Dial(ctx, url, nil) //nolint:bodyclose // Why: Closed by the library.
+	if err != nil {
+		return fmt.Errorf("failed to connect to gateway: %w", err)
+	}
+	defer conn.CloseNow() //nolint:errcheck // Why: Best effort.
+
+	// Messages may be large, e.g., READY contains every guild.
+	conn.SetReadLimit(1 << 24)
+
+	g := &gateway{conn: conn}
+
+	var hello struct {
+		HeartbeatInterval int64 `json:"heartbeat_interval"`
+	}
+	p, err := g.read(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Op != opHello {
+		return fmt.Errorf("expected hello from gateway, got opcode %d", p.Op)
+	}
+	if err := json.Unmarshal(p.D, &hello); err != nil {
+		return fmt.Errorf("failed to parse hello: %w", err)
+	}
+
+	identify := map[string]any{
+		"token":   token,
+		"intents": intents,
+		"properties": map[string]string{
+			"os":      "linux",
+			"browser": "factorio-docker",
+			"device":  "factorio-docker",
+		},
+	}
+	if err := g.write(ctx, opIdentify, identify); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- g.heartbeat(ctx, time.Duration(hello.HeartbeatInterval)*time.Millisecond)
+	}()
+
+	for {
+		p, err := g.read(ctx)
+		if err != nil {
+			select {
+			case hbErr := <-errCh:
+				return hbErr
+			default:
+			}
+			return err
+		}
+
+		switch p.Op {
+		case opDispatch:
+			if p.T != "MESSAGE_CREATE" {
+				continue
+			}
+
+			var msg Message
+			if err := json.Unmarshal(p.D, &msg); err != nil {
+				return fmt.Errorf("failed to parse message: %w", err)
+			}
+			onMessage(&msg)
+		case opHeartbeat:
+			if err := g.write(ctx, opHeartbeat, g.seq.Load()); err != nil {
+				return err
+			}
+		case opReconnect, opInvalidSession:
+			return errReconnect
+		}
+	}
+}
+
+// heartbeat sends a heartbeat every interval until the context is
+// canceled. For more information, see:
+// https://discord.com/developers/docs/topics/gateway#sending-heartbeats
+func (g *gateway) heartbeat(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if err := g.write(ctx, opHeartbeat, g.seq.Load()); err != nil {
+			g.conn.CloseNow() //nolint:errcheck // Why: Best effort, unblocks reads.
+			return err
+		}
+	}
+}
+
+// read reads the next payload, recording its sequence number.
+func (g *gateway) read(ctx context.Context) (*payload, error) {
+	var p payload
+	if err := wsjson.Read(ctx, g.conn, &p); err != nil {
+		return nil, fmt.Errorf("failed to read from gateway: %w", err)
+	}
+	if p.S != nil {
+		g.seq.Store(p.S)
+	}
+	return &p, nil
+}
+
+// write sends a payload with the provided opcode and data.
+func (g *gateway) write(ctx context.Context, op int, d any) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	if err := wsjson.Write(ctx, g.conn, payload{Op: op, D: b}); err != nil {
+		return fmt.Errorf("failed to write to gateway: %w", err)
+	}
+	return nil
+}
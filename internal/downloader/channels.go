@@ -66,3 +66,43 @@ func GetVersionForChannel(channel Channel) (string, error) {
 
 	return version, nil
 }
+
+// Release is a published headless release of Factorio along with the
+// channels it is the latest release of, if any.
+type Release struct {
+	factorio.Release
+
+	// Latest are the channels the release is currently the latest
+	// release of. Factorio doesn't publish which channel older releases
+	// were made in, so it is empty for every other release.
+	Latest []Channel
+}
+
+// ListReleases returns all published headless releases of Factorio,
+// sorted from oldest to newest.
+func ListReleases() ([]Release, error) {
+	latest, err := factorio.GetLatestReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	rels, err := factorio.GetReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Release, 0, len(rels))
+	for i := range rels {
+		r := Release{Release: rels[i]}
+		version := rels[i].Version.String()
+		if version == latest.Stable {
+			r.Latest = append(r.Latest, ChannelStable)
+		}
+		if version == latest.Experimental {
+			r.Latest = append(r.Latest, ChannelExperimental)
+		}
+		out = append(out, r)
+	}
+
+	return out, nil
+}
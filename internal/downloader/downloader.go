@@ -29,31 +29,37 @@ import (
 
 // Download downloads the specified Factorio version to the output
 // directory. If version is "stable" or "experimental", it will download
-// the latest stable or experimental version, respectively.
-func Download(ctx context.Context, version, sha256sum, outputDir string) error {
-	// Ensure the output directory is empty.
-	if files, err := os.ReadDir(outputDir); err == nil && len(files) > 0 {
-		return fmt.Errorf("output directory %s is not empty", outputDir)
+// the latest stable or experimental version, respectively. If version is
+// a constraint, the newest release satisfying it will be downloaded.
+//
+// A manifest of the installed files is returned, which can later be used
+// to verify the install. If progress is not nil, download progress is
+// reported to it.
+func Download(ctx context.Context, version, sha256sum, outputDir string,
+	progress factorio.ProgressReporter) (factorio.Manifest, error) {
+	// Ensure the output directory is empty, ignoring the state tracked
+	// by the wrapper.
+	if files, err := os.ReadDir(outputDir); err == nil {
+		for _, f := range files {
+			if !isWrapperFile(outputDir, f.Name()) {
+				return nil, fmt.Errorf("output directory %s is not empty", outputDir)
+			}
+		}
 	}
 
-	// Special case, resolve stable/experimental to the latest version of
-	// their respective channels.
-	if VersionIsChannel(version) {
-		var err error
-		version, err = GetVersionForChannel(Channel(version))
-		if err != nil {
-			return err
-		}
+	// Resolve channels and constraints to an exact version.
+	version, err := ResolveVersion(version)
+	if err != nil {
+		return nil, err
 	}
 
 	// If there's no SHA256 sum, get it.
 	if sha256sum == "" {
-		var err error
 		sha256sum, err = factorio.GetSHA256(version)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return factorio.DownloadVersion(ctx, version, sha256sum, outputDir)
+	return factorio.DownloadVersion(ctx, version, sha256sum, outputDir, progress)
 }
@@ -24,51 +24,145 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
+	"github.com/jaredallard/factorio-docker/internal/buildinfo"
 	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/notify"
 	"github.com/jaredallard/factorio-docker/internal/state"
 )
 
-// EnsureVersion ensures that the Factorio server is installed and up-to-date.
-func EnsureVersion(ctx context.Context, cfg *config.Config, log *slog.Logger) error {
-	st := state.Open(filepath.Join(cfg.InstallPath, "state.json"))
+// progressLogInterval is how often download progress is logged while
+// installing Factorio.
+const progressLogInterval = 5 * time.Second
+
+// StatePath returns the path to the state file for the install
+// described by cfg.
+func StatePath(cfg *config.Config) string {
+	return filepath.Join(cfg.InstallPath, "state.json")
+}
+
+// EnsureVersion ensures that the Factorio server is installed and
+// up-to-date, sending an event to n if it was upgraded.
+func EnsureVersion(ctx context.Context, cfg *config.Config, log *slog.Logger, n *notify.Notifier) error {
+	// Ensure the install path exists.
+	if _, err := os.Stat(cfg.InstallPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(cfg.InstallPath, 0o750); err != nil {
+			return fmt.Errorf("failed to create install directory: %w", err)
+		}
+	}
+
+	// Prevent other wrappers sharing the install from modifying it while
+	// we're using it.
+	statePath := StatePath(cfg)
+	lock, err := state.Acquire(ctx, statePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock state: %w", err)
+	}
+	defer lock.Release() //nolint:errcheck // Why: Best effort.
+
+	st, err := state.Open(statePath)
+	if err != nil {
+		return err
+	}
 
 	if st.Version == "" {
 		log.Info("No version of Factorio installed, installing...")
+	}
 
-		// Ensure the install path exists.
-		if _, err := os.Stat(cfg.InstallPath); os.IsNotExist(err) {
-			if err := os.MkdirAll(cfg.InstallPath, 0o750); err != nil {
-				return fmt.Errorf("failed to create install directory: %w", err)
-			}
-		}
+	// If we're installing a channel or constraint, resolve it to an
+	// exact version.
+	requested := cfg.Version
+	ver, err := ResolveVersion(cfg.Version)
+	if err != nil {
+		return err
+	}
+	if ver != cfg.Version {
+		log.Info("Resolved version", "requested", cfg.Version, "version", ver)
+		cfg.Version = ver
 	}
 
-	// If we're installing a channel, resolve it to the latest version.
-	if VersionIsChannel(cfg.Version) {
-		// If the version is a channel, resolve it to the latest version.
-		ver, err := GetVersionForChannel(Channel(cfg.Version))
+	cfg.Version = targetVersion(log, st, requested, ver)
+
+	// If the installed version is the requested version, we're done
+	// unless the install has been corrupted.
+	if st.Version == cfg.Version {
+		ok, err := verifyInstall(cfg, log, st)
 		if err != nil {
 			return err
 		}
+		if ok {
+			log.Info("Factorio is desired version")
+			return nil
+		}
 
-		oldVer := cfg.Version
-		cfg.Version = ver
+		log.Warn("Installed Factorio failed verification, reinstalling")
+	}
 
-		log.Info("Resolved channel", "channel", oldVer, "version", ver)
+	if st.Version != cfg.Version {
+		if err := checkDowngrade(cfg, log); err != nil {
+			return err
+		}
 	}
 
-	// If the installed version is the requested version, we're done.
-	if st.Version == cfg.Version {
-		log.Info("Factorio is desired version")
-		return nil
+	previous := st.Version
+	if previous != "" && previous != cfg.Version {
+		// Keep the save from before the change, since Factorio migrates
+		// saves on load and older versions can't load them.
+		path, err := backupSave(cfg, previous, cfg.Version)
+		if err != nil {
+			n.Notify(notify.Event{
+				Type:    notify.EventBackupFailed,
+				Message: fmt.Sprintf("Failed to back up save before installing Factorio %s", cfg.Version),
+				Version: previous,
+				Error:   err.Error(),
+			})
+
+			// A version requested explicitly can't be ignored, but a
+			// channel or constraint can wait for the next start.
+			if ver == requested {
+				return fmt.Errorf("failed to back up save before installing Factorio %s: %w", cfg.Version, err)
+			}
+			log.Error("Failed to back up save, keeping installed version", "version", cfg.Version, "err", err)
+			cfg.Version = previous
+			return nil
+		}
+		if path != "" {
+			log.Info("Backed up save", "path", path)
+			n.Notify(notify.Event{
+				Type:    notify.EventBackupSucceeded,
+				Message: fmt.Sprintf("Backed up save before installing Factorio %s", cfg.Version),
+				Version: previous,
+				Path:    path,
+			})
+		}
+
+		if cfg.RollbackTimeout > 0 {
+			if err := keepPrevious(cfg, st); err != nil {
+				return err
+			}
+		}
 	}
 
-	log.Info("Installing Factorio", "version", cfg.Version, "previous", st.Version)
+	log.Info("Installing Factorio", "version", cfg.Version, "previous", previous)
 
-	// Ensure the directory is empty.
+	// Mark nothing as installed before touching the install, so that an
+	// interrupted install is not mistaken for a complete one.
+	st.Version = ""
+	st.Manifest = nil
+	if err := st.Save(); err != nil {
+		return fmt.Errorf("failed to track installed version: %w", err)
+	}
+
+	// Ensure the directory is empty, except for our state.
 	if files, err := os.ReadDir(cfg.InstallPath); err == nil && len(files) > 0 {
 		for _, f := range files {
+			if isWrapperFile(cfg.InstallPath, f.Name()) {
+				continue
+			}
+
 			if err := os.RemoveAll(filepath.Join(cfg.InstallPath, f.Name())); err != nil {
 				return fmt.Errorf("failed to remove existing Factorio installation: %w", err)
 			}
@@ -76,14 +170,170 @@ func EnsureVersion(ctx context.Context, cfg *config.Config, log *slog.Logger) er
 	}
 
 	// The installed version is not the requested version, download it.
-	if err := Download(ctx, cfg.Version, "", cfg.InstallPath); err != nil {
+	sha256sum, err := factorio.GetSHA256(cfg.Version)
+	if err != nil {
+		return err
+	}
+
+	progress := factorio.NewLogProgressReporter(log, progressLogInterval)
+	m, err := Download(ctx, cfg.Version, sha256sum, cfg.InstallPath, progress)
+	if err != nil {
 		return err
 	}
 
 	st.Version = cfg.Version
+	st.Manifest = m
+	st.AddInstall(state.Install{
+		Version:        cfg.Version,
+		InstalledAt:    time.Now().UTC(),
+		Channel:        requested,
+		SHA256:         sha256sum,
+		WrapperVersion: buildinfo.Version(),
+	})
 	if err := st.Save(); err != nil {
 		return fmt.Errorf("failed to track installed version: %w", err)
 	}
 
+	if previous != "" && previous != cfg.Version {
+		n.Notify(versionChangedEvent(previous, cfg.Version))
+	}
+
 	return nil
 }
+
+// versionChangedEvent returns the event sent when the installed version
+// of Factorio changes from previous to current.
+func versionChangedEvent(previous, current string) notify.Event {
+	typ, verb := notify.EventVersionUpgraded, "upgraded"
+	pv, perr := factorio.ParseVersion(previous)
+	cv, cerr := factorio.ParseVersion(current)
+	if perr == nil && cerr == nil && cv.LessThan(pv) {
+		typ, verb = notify.EventVersionDowngraded, "downgraded"
+	}
+
+	return notify.Event{
+		Type:            typ,
+		Message:         fmt.Sprintf("Factorio %s from %s to %s", verb, previous, current),
+		Version:         current,
+		PreviousVersion: previous,
+	}
+}
+
+// targetVersion returns the version to install, given the requested
+// version and the version it resolved to. Versions that were rolled back
+// are skipped until a newer one is released, unless they were requested
+// explicitly.
+func targetVersion(log *slog.Logger, st *state.State, requested, resolved string) string {
+	if resolved == requested || !st.IsBad(resolved) || st.Version == "" {
+		return resolved
+	}
+
+	log.Warn("Skipping version of Factorio that failed to start", "version", resolved, "installed", st.Version)
+	return st.Version
+}
+
+// checkDowngrade returns an error if the save the server will load was
+// made with a newer version of Factorio than cfg.Version, which can't
+// load it, unless downgrades are allowed.
+func checkDowngrade(cfg *config.Config, log *slog.Logger) error {
+	path, err := factorio.LatestSave(cfg.ServerDataPath)
+	if err != nil || path == "" {
+		return err
+	}
+
+	h, err := factorio.ReadSaveHeader(path)
+	if err != nil {
+		log.Warn("Failed to read save header, unable to check if it can be loaded", "err", err)
+		return nil
+	}
+
+	target, err := factorio.ParseVersion(cfg.Version)
+	if err != nil || factorio.CanLoad(h, target) {
+		return err
+	}
+
+	if cfg.AllowDowngrade {
+		log.Warn("Installing a version of Factorio older than the save, which it will fail to load",
+			"path", path, "save_version", h.Version.String(), "version", cfg.Version)
+		return nil
+	}
+
+	return fmt.Errorf("refusing to install Factorio %s: the save %s was made with Factorio %s, which %s can't load "+
+		"(set %s=true to install it anyway)",
+		cfg.Version, path, h.Version, cfg.Version, cfg.EnvName(&cfg.AllowDowngrade))
+}
+
+// MarkStarted records that the server successfully started on the
+// installed version of Factorio, removing the previous install kept to
+// roll back to.
+func MarkStarted(ctx context.Context, cfg *config.Config) error {
+	statePath := StatePath(cfg)
+	lock, err := state.Acquire(ctx, statePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock state: %w", err)
+	}
+	defer lock.Release() //nolint:errcheck // Why: Best effort.
+
+	st, err := state.Open(statePath)
+	if err != nil {
+		return err
+	}
+
+	i := st.LastInstall(st.Version)
+	if i == nil || (i.Started && st.Previous == "") {
+		return nil
+	}
+	i.Started = true
+
+	// A version that was rolled back and then requested explicitly may
+	// have been fixed by something else, e.g., a mod update.
+	st.BadVersions = slices.DeleteFunc(st.BadVersions, func(v string) bool { return v == st.Version })
+
+	if err := forgetPrevious(cfg, st); err != nil {
+		return err
+	}
+	return st.Save()
+}
+
+// maxReportedProblems is the maximum number of verification problems to
+// log, to prevent flooding the logs when an install is badly damaged.
+const maxReportedProblems = 10
+
+// verifyInstall checks the installed Factorio server against the
+// manifest recorded when it was installed. Returns false if the install
+// is corrupted or has been modified.
+func verifyInstall(cfg *config.Config, log *slog.Logger, st *state.State) (bool, error) {
+	var mode factorio.VerifyMode
+	switch cfg.VerifyInstall {
+	case "off":
+		return true, nil
+	case "fast":
+		mode = factorio.VerifyFast
+	case "full":
+		mode = factorio.VerifyFull
+	default:
+		return false, fmt.Errorf("unknown install verification mode %q", cfg.VerifyInstall)
+	}
+
+	// Installs made before manifests were recorded can't be verified.
+	if len(st.Manifest) == 0 {
+		log.Info("No manifest recorded for installed Factorio, skipping verification")
+		return true, nil
+	}
+
+	log.Info("Verifying installed Factorio", "mode", cfg.VerifyInstall, "files", len(st.Manifest))
+	problems, err := st.Manifest.Verify(cfg.InstallPath, mode)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify install: %w", err)
+	}
+
+	for i, p := range problems {
+		if i == maxReportedProblems {
+			log.Warn("Additional files failed verification", "count", len(problems)-i)
+			break
+		}
+		log.Warn("File failed verification", "path", p.Path, "reason", p.Reason)
+	}
+
+	return len(problems) == 0, nil
+}
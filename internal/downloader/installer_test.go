@@ -0,0 +1,100 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/factorio/factoriotest"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+	"github.com/jaredallard/factorio-docker/internal/state"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckDowngrade(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{ServerDataPath: t.TempDir(), Version: "2.0.20"}
+
+	// No saves.
+	assert.NilError(t, downloader.CheckDowngrade(cfg, log))
+
+	assert.NilError(t, factoriotest.WriteSave(filepath.Join(cfg.ServerDataPath, "saves", "world.zip"), "level.dat0",
+		factoriotest.Header(factorio.Version{Major: 2, Patch: 28}), false))
+	err := downloader.CheckDowngrade(cfg, log)
+	assert.ErrorContains(t, err, "was made with Factorio 2.0.28")
+	assert.ErrorContains(t, err, "FACTORIO_ALLOW_DOWNGRADE=true")
+
+	cfg.AllowDowngrade = true
+	assert.NilError(t, downloader.CheckDowngrade(cfg, log))
+
+	cfg.AllowDowngrade = false
+	cfg.Version = "2.0.28"
+	assert.NilError(t, downloader.CheckDowngrade(cfg, log))
+}
+
+func TestTargetVersionSkipsBadVersions(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	st := &state.State{Version: "2.0.28", BadVersions: []string{"2.0.30"}}
+
+	// Resolved from a channel or constraint, so skipped.
+	assert.Equal(t, downloader.TargetVersion(log, st, "stable", "2.0.30"), "2.0.28")
+	assert.Equal(t, downloader.TargetVersion(log, st, "~2.0", "2.0.30"), "2.0.28")
+	assert.Equal(t, downloader.TargetVersion(log, st, "stable", "2.0.31"), "2.0.31")
+
+	// Pinned explicitly, so installed anyway.
+	assert.Equal(t, downloader.TargetVersion(log, st, "2.0.30", "2.0.30"), "2.0.30")
+}
+
+func TestVersionChangedEvent(t *testing.T) {
+	e := downloader.VersionChangedEvent("1.1.110", "2.0.15")
+	assert.Equal(t, e.Type, notify.EventVersionUpgraded)
+	assert.Equal(t, e.Message, "Factorio upgraded from 1.1.110 to 2.0.15")
+
+	e = downloader.VersionChangedEvent("2.0.15", "2.0.9")
+	assert.Equal(t, e.Type, notify.EventVersionDowngraded)
+	assert.Equal(t, e.Message, "Factorio downgraded from 2.0.15 to 2.0.9")
+	assert.Equal(t, e.Version, "2.0.9")
+	assert.Equal(t, e.PreviousVersion, "2.0.15")
+}
+
+func TestEnsureVersionFailsIfPinnedVersionCantBeBackedUp(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{ServerDataPath: t.TempDir(), InstallPath: t.TempDir(), Version: "2.0.28"}
+
+	st, err := state.Open(downloader.StatePath(cfg))
+	assert.NilError(t, err)
+	st.Version = "2.0.20"
+	assert.NilError(t, st.Save())
+
+	assert.NilError(t, factoriotest.WriteSave(filepath.Join(cfg.ServerDataPath, "saves", "world.zip"), "level.dat0",
+		factoriotest.Header(factorio.Version{Major: 2, Patch: 20}), false))
+
+	// Backups can't be written where a file is in the way.
+	assert.NilError(t, os.WriteFile(filepath.Join(cfg.ServerDataPath, "pre-upgrade"), nil, 0o600))
+
+	err = downloader.EnsureVersion(context.Background(), cfg, log, nil)
+	assert.ErrorContains(t, err, "failed to back up save before installing Factorio 2.0.28")
+}
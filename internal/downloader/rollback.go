@@ -0,0 +1,192 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+	"github.com/jaredallard/factorio-docker/internal/state"
+)
+
+// previousDir is the directory in the install path that the previous
+// install is kept in until an upgrade has started successfully.
+const previousDir = ".previous"
+
+// isWrapperFile returns true if name, a file in the install path dir,
+// is managed by the wrapper rather than part of the install.
+func isWrapperFile(dir, name string) bool {
+	return name == previousDir || state.IsStateFile(filepath.Join(dir, "state.json"), name)
+}
+
+// moveInstall moves every file of the install in src to dest, which
+// must exist.
+func moveInstall(src, dest string) error {
+	files, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if isWrapperFile(src, f.Name()) {
+			continue
+		}
+		if err := os.Rename(filepath.Join(src, f.Name()), filepath.Join(dest, f.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keepPrevious moves the installed version into the previous install
+// directory, so that it can be restored by Rollback. If the installed
+// version never started, the existing previous install is kept
+// instead, since it's the last one known to work.
+func keepPrevious(cfg *config.Config, st *state.State) error {
+	if st.Previous != "" {
+		if i := st.LastInstall(st.Version); i != nil && !i.Started {
+			return nil
+		}
+	}
+
+	dir := filepath.Join(cfg.InstallPath, previousDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove previous install: %w", err)
+	}
+	if err := os.Mkdir(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create previous install directory: %w", err)
+	}
+	if err := moveInstall(cfg.InstallPath, dir); err != nil {
+		return fmt.Errorf("failed to keep previous install: %w", err)
+	}
+
+	st.Previous = st.Version
+	st.PreviousManifest = st.Manifest
+	return nil
+}
+
+// forgetPrevious removes the previous install, once it is no longer
+// needed.
+func forgetPrevious(cfg *config.Config, st *state.State) error {
+	st.Previous = ""
+	st.PreviousManifest = nil
+	if err := os.RemoveAll(filepath.Join(cfg.InstallPath, previousDir)); err != nil {
+		return fmt.Errorf("failed to remove previous install: %w", err)
+	}
+	return nil
+}
+
+// CanRollback returns true if the installed version was upgraded to
+// and has not started yet, meaning Rollback can restore the previous
+// version.
+func CanRollback(cfg *config.Config) (bool, error) {
+	st, err := state.Open(StatePath(cfg))
+	if err != nil {
+		return false, err
+	}
+
+	return st.Previous != "", nil
+}
+
+// IsBadVersion returns true if version was rolled back because it
+// failed to start.
+func IsBadVersion(cfg *config.Config, version string) (bool, error) {
+	st, err := state.Open(StatePath(cfg))
+	if err != nil {
+		return false, err
+	}
+
+	return st.IsBad(version), nil
+}
+
+// Rollback restores the version of Factorio installed before the last
+// upgrade and the save backed up before it, sending an event to n. The
+// installed version is recorded as bad, so that it isn't installed
+// again unless requested explicitly.
+func Rollback(ctx context.Context, cfg *config.Config, log *slog.Logger, n *notify.Notifier) error {
+	statePath := StatePath(cfg)
+	lock, err := state.Acquire(ctx, statePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock state: %w", err)
+	}
+	defer lock.Release() //nolint:errcheck // Why: Best effort.
+
+	st, err := state.Open(statePath)
+	if err != nil {
+		return err
+	}
+	if st.Previous == "" || st.Version == "" {
+		return fmt.Errorf("no upgrade to roll back")
+	}
+
+	bad := st.Version
+	log.Warn("Rolling back Factorio", "version", bad, "previous", st.Previous)
+
+	// Mark nothing as installed while swapping the installs, so that an
+	// interrupted rollback is not mistaken for a complete one.
+	st.MarkBad(bad)
+	st.Version = ""
+	st.Manifest = nil
+	if err := st.Save(); err != nil {
+		return fmt.Errorf("failed to track installed version: %w", err)
+	}
+
+	files, err := os.ReadDir(cfg.InstallPath)
+	if err != nil {
+		return fmt.Errorf("failed to read install directory: %w", err)
+	}
+	for _, f := range files {
+		if isWrapperFile(cfg.InstallPath, f.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cfg.InstallPath, f.Name())); err != nil {
+			return fmt.Errorf("failed to remove Factorio installation: %w", err)
+		}
+	}
+	if err := moveInstall(filepath.Join(cfg.InstallPath, previousDir), cfg.InstallPath); err != nil {
+		return fmt.Errorf("failed to restore previous install: %w", err)
+	}
+
+	previous := st.Previous
+	st.Version = previous
+	st.Manifest = st.PreviousManifest
+	if err := forgetPrevious(cfg, st); err != nil {
+		return err
+	}
+	if err := st.Save(); err != nil {
+		return fmt.Errorf("failed to track installed version: %w", err)
+	}
+
+	if err := restoreSave(cfg, previous); err != nil {
+		return err
+	}
+
+	n.Notify(notify.Event{
+		Type:            notify.EventVersionRolledBack,
+		Message:         fmt.Sprintf("Factorio %s failed to start, rolled back to %s", bad, previous),
+		Version:         previous,
+		PreviousVersion: bad,
+	})
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/state"
+	"gotest.tools/v3/assert"
+)
+
+// writeFile writes contents to path, creating its parent directories.
+func writeFile(t *testing.T, path, contents string) {
+	assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	assert.NilError(t, os.WriteFile(path, []byte(contents), 0o600))
+}
+
+func TestRollback(t *testing.T) {
+	cfg := &config.Config{InstallPath: t.TempDir(), ServerDataPath: t.TempDir()}
+
+	writeFile(t, filepath.Join(cfg.InstallPath, "bin", "factorio"), "new")
+	writeFile(t, filepath.Join(cfg.InstallPath, ".previous", "bin", "factorio"), "old")
+	writeFile(t, filepath.Join(cfg.ServerDataPath, "saves", "world.zip"), "upgraded save")
+	writeFile(t, filepath.Join(cfg.ServerDataPath, "saves", "_autosave1.zip"), "upgraded autosave")
+	past := time.Now().Add(-time.Minute)
+	assert.NilError(t, os.Chtimes(filepath.Join(cfg.ServerDataPath, "saves", "_autosave1.zip"), past, past))
+	writeFile(t, filepath.Join(downloader.PreUpgradeDir(cfg, "2.0.28"), "world.zip"), "old save")
+
+	st, err := state.Open(downloader.StatePath(cfg))
+	assert.NilError(t, err)
+	st.Version = "2.0.30"
+	st.Previous = "2.0.28"
+	assert.NilError(t, st.Save())
+
+	ok, err := downloader.CanRollback(cfg)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+
+	assert.NilError(t, downloader.Rollback(context.Background(), cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil))
+
+	b, err := os.ReadFile(filepath.Join(cfg.InstallPath, "bin", "factorio"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "old")
+	_, err = os.Stat(filepath.Join(cfg.InstallPath, ".previous"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// The save from before the upgrade is loaded next.
+	save, err := factorio.LatestSave(cfg.ServerDataPath)
+	assert.NilError(t, err)
+	assert.Equal(t, save, filepath.Join(cfg.ServerDataPath, "saves", "world.zip"))
+	b, err = os.ReadFile(save)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "old save")
+
+	st, err = state.Open(downloader.StatePath(cfg))
+	assert.NilError(t, err)
+	assert.Equal(t, st.Version, "2.0.28")
+	assert.Equal(t, st.Previous, "")
+	assert.Assert(t, st.IsBad("2.0.30"))
+
+	ok, err = downloader.CanRollback(cfg)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// PreUpgradeDir returns the directory the save is backed up to before
+// upgrading from version.
+func PreUpgradeDir(cfg *config.Config, version string) string {
+	return filepath.Join(cfg.ServerDataPath, "pre-upgrade", version)
+}
+
+// SnapshotFile is the name of the file in a pre-upgrade directory that
+// describes the save backed up in it.
+const SnapshotFile = "snapshot.json"
+
+// Snapshot describes a save backed up before changing the installed
+// version of Factorio.
+type Snapshot struct {
+	// Version is the version of Factorio that was installed when the
+	// save was backed up.
+	Version string `json:"version"`
+
+	// TargetVersion is the version of Factorio that was being installed.
+	TargetVersion string `json:"target_version"`
+
+	// Save is the file name of the save.
+	Save string `json:"save"`
+
+	// CreatedAt is when the save was backed up.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Mods are the mods that were installed.
+	Mods []factorio.Mod `json:"mods"`
+}
+
+// backupSave copies the save the server would load to the pre-upgrade
+// directory of version, along with a Snapshot describing it, before
+// target is installed. The path to the backup is returned. If there is
+// no save, an empty string is returned.
+func backupSave(cfg *config.Config, version, target string) (string, error) {
+	save, err := factorio.LatestSave(cfg.ServerDataPath)
+	if err != nil || save == "" {
+		return "", err
+	}
+
+	mods, err := factorio.ListMods(cfg.ServerDataPath)
+	if err != nil {
+		return "", err
+	}
+
+	dir := PreUpgradeDir(cfg, version)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to remove old backup: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	dest := filepath.Join(dir, filepath.Base(save))
+	if err := copyFile(save, dest); err != nil {
+		return "", fmt.Errorf("failed to back up save: %w", err)
+	}
+
+	b, err := json.MarshalIndent(Snapshot{
+		Version:       version,
+		TargetVersion: target,
+		Save:          filepath.Base(save),
+		CreatedAt:     time.Now().UTC(),
+		Mods:          mods,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, SnapshotFile), b, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+
+	return dest, nil
+}
+
+// restoreSave copies the save backed up before upgrading from version
+// back to the saves directory, as the most recent save so that it is
+// the one loaded by the server.
+func restoreSave(cfg *config.Config, version string) error {
+	files, err := os.ReadDir(PreUpgradeDir(cfg, version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".zip" {
+			continue
+		}
+
+		dest := filepath.Join(factorio.SavesDir(cfg.ServerDataPath), f.Name())
+		if err := copyFile(filepath.Join(PreUpgradeDir(cfg, version), f.Name()), dest); err != nil {
+			return fmt.Errorf("failed to restore save: %w", err)
+		}
+
+		now := time.Now()
+		return os.Chtimes(dest, now, now)
+	}
+
+	return nil
+}
+
+// copyFile copies the file at src to dest, replacing it.
+func copyFile(src, dest string) error {
+	srcF, err := os.Open(src) //nolint:gosec // Why: By design.
+	if err != nil {
+		return err
+	}
+	defer srcF.Close() //nolint:errcheck // Why: Best effort.
+
+	//nolint:gosec // Why: By design.
+	destF, err := os.OpenFile(dest+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(destF.Name()) //nolint:errcheck // Why: Best effort, fails once renamed.
+	defer destF.Close()           //nolint:errcheck // Why: Best effort.
+
+	if _, err := io.Copy(destF, srcF); err != nil {
+		return err
+	}
+	if err := destF.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(destF.Name(), dest)
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/downloader"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestBackupSaveWritesSnapshot(t *testing.T) {
+	cfg := &config.Config{ServerDataPath: t.TempDir()}
+	writeFile(t, filepath.Join(cfg.ServerDataPath, "saves", "world.zip"), "save")
+	writeFile(t, filepath.Join(cfg.ServerDataPath, "mods", "even_distribution_2.0.2.zip"), "")
+
+	path, err := downloader.BackupSave(cfg, "2.0.28", "2.0.30")
+	assert.NilError(t, err)
+	assert.Equal(t, path, filepath.Join(downloader.PreUpgradeDir(cfg, "2.0.28"), "world.zip"))
+
+	b, err := os.ReadFile(filepath.Join(downloader.PreUpgradeDir(cfg, "2.0.28"), downloader.SnapshotFile))
+	assert.NilError(t, err)
+
+	var snap downloader.Snapshot
+	assert.NilError(t, json.Unmarshal(b, &snap))
+	assert.Equal(t, snap.Version, "2.0.28")
+	assert.Equal(t, snap.TargetVersion, "2.0.30")
+	assert.Equal(t, snap.Save, "world.zip")
+	assert.DeepEqual(t, snap.Mods, []factorio.Mod{{Name: "even_distribution", Version: "2.0.2", Enabled: true}})
+}
+
+func TestBackupSaveWithoutSaves(t *testing.T) {
+	cfg := &config.Config{ServerDataPath: t.TempDir()}
+
+	path, err := downloader.BackupSave(cfg, "2.0.28", "2.0.30")
+	assert.NilError(t, err)
+	assert.Equal(t, path, "")
+}
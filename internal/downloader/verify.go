@@ -0,0 +1,49 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader
+
+import (
+	"context"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// Verify re-hashes the Factorio install in dir against the official
+// release of the provided version, returning every file that is missing
+// or has been modified. The version may be anything accepted by
+// ResolveVersion. If progress is not nil, download progress is reported
+// to it.
+func Verify(ctx context.Context, version, dir string,
+	progress factorio.ProgressReporter) ([]factorio.ManifestProblem, error) {
+	version, err := ResolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	sha256sum, err := factorio.GetSHA256(version)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := factorio.FetchManifest(ctx, version, sha256sum, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Verify(dir, factorio.VerifyFull)
+}
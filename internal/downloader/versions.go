@@ -0,0 +1,64 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package downloader
+
+import (
+	"fmt"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// ResolveVersion resolves the provided version into an exact Factorio
+// version. The version can be one of:
+//
+//   - A channel ('stable' or 'experimental'), which resolves to the
+//     latest release of that channel.
+//   - An exact version (e.g., 2.0.28), which is returned as-is.
+//   - A version constraint (e.g., '~2.0' or '>=1.1.100 <2.0'), which
+//     resolves to the newest published release satisfying it.
+func ResolveVersion(version string) (string, error) {
+	if VersionIsChannel(version) {
+		return GetVersionForChannel(Channel(version))
+	}
+
+	if _, err := factorio.ParseVersion(version); err == nil {
+		return version, nil
+	}
+
+	c, err := factorio.ParseConstraint(version)
+	if err != nil {
+		return "", fmt.Errorf("version %q is not a channel, version or constraint: %w", version, err)
+	}
+
+	rels, err := factorio.GetReleases()
+	if err != nil {
+		return "", fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	versions := make([]factorio.Version, 0, len(rels))
+	for i := range rels {
+		versions = append(versions, rels[i].Version)
+	}
+
+	v, ok := c.Latest(versions)
+	if !ok {
+		return "", fmt.Errorf("no published release satisfies constraint %q", version)
+	}
+
+	return v.String(), nil
+}
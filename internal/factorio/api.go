@@ -22,16 +22,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 )
 
+// baseURL is the base URL of the factorio.com API. It is a variable so
+// that tests are able to point it at a fake server.
+var baseURL = "https://factorio.com"
+
 // getAllowedFileNames returns the allowed file names for a Factorio
 // version.
 func getAllowedFileNames(version string) []string {
-	return []string{
-		fmt.Sprintf("factorio_headless_x64_%s.tar.xz", version),
-		fmt.Sprintf("factorio-headless_linux_%s.tar.xz", version),
+	fileNames := make([]string, 0, len(headlessFilePrefixes))
+	for _, prefix := range headlessFilePrefixes {
+		fileNames = append(fileNames, prefix+version+".tar.xz")
 	}
+	return fileNames
 }
 
 // Releases contains the latest releases of Factorio based on channels.
@@ -57,7 +63,7 @@ type releaseResponse struct {
 
 // GetLatestReleases gets the latest releases of Factorio.
 func GetLatestReleases() (*Releases, error) {
-	resp, err := http.Get("https://factorio.com/api/latest-releases")
+	resp, err := http.Get(baseURL + "/api/latest-releases")
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +80,96 @@ func GetLatestReleases() (*Releases, error) {
 	}, nil
 }
 
+// Release is a published headless release of Factorio.
+type Release struct {
+	// Version is the version of the release.
+	Version Version
+
+	// SHA256 is the SHA256 sum of the release's tarball.
+	SHA256 string
+}
+
+// headlessFilePrefixes are the file name prefixes used by headless
+// releases. The naming scheme changed over time, so both are accepted.
+var headlessFilePrefixes = []string{
+	"factorio_headless_x64_",
+	"factorio-headless_linux_",
+}
+
+// parseHeadlessFileName returns the version of a headless release based
+// on the provided file name. If the file name is not a headless
+// release, false is returned.
+func parseHeadlessFileName(fileName string) (Version, bool) {
+	for _, prefix := range headlessFilePrefixes {
+		if !strings.HasPrefix(fileName, prefix) || !strings.HasSuffix(fileName, ".tar.xz") {
+			continue
+		}
+
+		v, err := ParseVersion(strings.TrimSuffix(strings.TrimPrefix(fileName, prefix), ".tar.xz"))
+		if err != nil {
+			return Version{}, false
+		}
+		return v, true
+	}
+
+	return Version{}, false
+}
+
+// GetReleases returns all published headless releases of Factorio,
+// sorted from oldest to newest. Releases are discovered through the
+// published SHA256 sums, which contain every release ever made.
+func GetReleases() ([]Release, error) {
+	resp, err := http.Get(baseURL + "/download/sha256sums/")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // Why: Best effort.
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get SHA256 sums: unexpected status %s", resp.Status)
+	}
+
+	// Format: SHA256_HASH FILENAME
+	seen := make(map[Version]struct{})
+	var rels []Release
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		v, ok := parseHeadlessFileName(fields[1])
+		if !ok {
+			continue
+		}
+
+		// Some releases are published under both naming schemes, only
+		// keep the first one.
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+
+		rels = append(rels, Release{Version: v, SHA256: fields[0]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SHA256 sums: %w", err)
+	}
+
+	slices.SortFunc(rels, func(a, b Release) int {
+		return a.Version.Compare(b.Version)
+	})
+
+	return rels, nil
+}
+
 // GetSHA256 gets the SHA256 hash of a Factorio version. This will only
 // return the hash of the headless version.
 func GetSHA256(version string) (string, error) {
 	allowedFileNames := getAllowedFileNames(version)
 
-	resp, err := http.Get("https://factorio.com/download/sha256sums/")
+	resp, err := http.Get(baseURL + "/download/sha256sums/")
 	if err != nil {
 		return "", err
 	}
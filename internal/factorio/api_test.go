@@ -32,9 +32,14 @@ func TestCanGetLatestAndSHA256(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	t.Log("Downloading", rels.Stable, "to", tmpDir, "sha256:", stableSHA)
-	err = factorio.DownloadVersion(context.Background(), rels.Stable, stableSHA, tmpDir)
+	m, err := factorio.DownloadVersion(context.Background(), rels.Stable, stableSHA, tmpDir, nil)
 	assert.NilError(t, err)
 
+	// Ensure the returned manifest matches what was extracted
+	problems, err := m.Verify(tmpDir, factorio.VerifyFull)
+	assert.NilError(t, err)
+	assert.Equal(t, len(problems), 0)
+
 	// Ensure it contains an expected file
 	_, err = os.Stat(filepath.Join(tmpDir, "bin", "x64", "factorio"))
 	assert.NilError(t, err)
@@ -0,0 +1,131 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// serverReadyMarker is logged by the server once it has loaded a save
+// and is accepting players.
+const serverReadyMarker = "changing state from(CreatingGame) to(InGame)"
+
+// IsReadyLine returns true if the provided line, as output by the
+// server, indicates that it has started and is accepting players. Only
+// the server's own log lines are considered, so that players can't
+// fake it by sending the marker in chat.
+func IsReadyLine(line string) bool {
+	if _, ok := ParseConsoleEvent(line); ok {
+		return false
+	}
+
+	m := logLineRegexp.FindStringSubmatch(line)
+	return m != nil && m[1] == "Info" && strings.Contains(line[len(m[0]):], serverReadyMarker)
+}
+
+// logLineRegexp matches the log lines output by the server, e.g.,
+// "   1.234 Warning Foo.cpp:12: message", capturing the level.
+var logLineRegexp = regexp.MustCompile(`^\s*\d+\.\d+ (Verbose|Info|Warning|Error) `)
+
+// LineLevel returns the log level of a line output by the server. Lines
+// that don't have a level, such as console events, are
+// slog.LevelInfo.
+func LineLevel(line string) slog.Level {
+	m := logLineRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return slog.LevelInfo
+	}
+
+	switch m[1] {
+	case "Verbose":
+		return slog.LevelDebug
+	case "Warning":
+		return slog.LevelWarn
+	case "Error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// ConsoleEventType is the type of a ConsoleEvent.
+type ConsoleEventType string
+
+// Contains all supported console event types.
+const (
+	// ConsoleEventChat is a chat message sent by a player.
+	ConsoleEventChat ConsoleEventType = "chat"
+
+	// ConsoleEventJoin is a player joining the game.
+	ConsoleEventJoin ConsoleEventType = "join"
+
+	// ConsoleEventLeave is a player leaving the game.
+	ConsoleEventLeave ConsoleEventType = "leave"
+)
+
+// ServerPlayer is the name used for messages sent by the server itself,
+// e.g., over RCON.
+const ServerPlayer = "<server>"
+
+// ConsoleEvent is an event parsed from the server's console output.
+type ConsoleEvent struct {
+	Type ConsoleEventType
+
+	// Player is the name of the player that caused the event.
+	Player string
+
+	// Message is the chat message, for chat events.
+	Message string
+}
+
+// consoleEventRegexp matches the events written to the console by the
+// server, e.g., "2024-01-02 03:04:05 [CHAT] player: hello".
+var consoleEventRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} \[(CHAT|JOIN|LEAVE)\] (.*)$`)
+
+// ParseConsoleEvent parses a line output by the server into a console
+// event, returning false if the line isn't one.
+func ParseConsoleEvent(line string) (ConsoleEvent, bool) {
+	m := consoleEventRegexp.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+	if m == nil {
+		return ConsoleEvent{}, false
+	}
+
+	switch m[1] {
+	case "CHAT":
+		player, msg, ok := strings.Cut(m[2], ": ")
+		if !ok {
+			return ConsoleEvent{}, false
+		}
+
+		// Players on another force have it appended to their name,
+		// e.g., "player [enemy]".
+		if i := strings.Index(player, " ["); i > 0 && strings.HasSuffix(player, "]") {
+			player = player[:i]
+		}
+		return ConsoleEvent{Type: ConsoleEventChat, Player: player, Message: msg}, true
+	case "JOIN":
+		player, ok := strings.CutSuffix(m[2], " joined the game")
+		return ConsoleEvent{Type: ConsoleEventJoin, Player: player}, ok
+	case "LEAVE":
+		player, ok := strings.CutSuffix(m[2], " left the game")
+		return ConsoleEvent{Type: ConsoleEventLeave, Player: player}, ok
+	}
+
+	return ConsoleEvent{}, false
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseConsoleEvent(t *testing.T) {
+	tests := map[string]factorio.ConsoleEvent{
+		"2024-01-02 03:04:05 [CHAT] alice: hello: world": {
+			Type: factorio.ConsoleEventChat, Player: "alice", Message: "hello: world",
+		},
+		"2024-01-02 03:04:05 [CHAT] bob [enemy]: hi": {
+			Type: factorio.ConsoleEventChat, Player: "bob", Message: "hi",
+		},
+		"2024-01-02 03:04:05 [JOIN] alice joined the game": {
+			Type: factorio.ConsoleEventJoin, Player: "alice",
+		},
+		"2024-01-02 03:04:05 [LEAVE] alice left the game\r\n": {
+			Type: factorio.ConsoleEventLeave, Player: "alice",
+		},
+	}
+
+	for line, want := range tests {
+		got, ok := factorio.ParseConsoleEvent(line)
+		assert.Assert(t, ok, line)
+		assert.DeepEqual(t, got, want)
+	}
+
+	_, ok := factorio.ParseConsoleEvent("   1.234 Info ServerMultiplayerManager.cpp:123: [CHAT] fake: message")
+	assert.Assert(t, !ok)
+}
+
+func TestIsReadyLine(t *testing.T) {
+	assert.Assert(t, factorio.IsReadyLine(
+		"   2.345 Info ServerMultiplayerManager.cpp:923: updateTick(0) changing state from(CreatingGame) to(InGame)"))
+
+	// Players can't fake the server becoming ready from chat.
+	for _, line := range []string{
+		"2024-01-02 03:04:05 [CHAT] mallory: changing state from(CreatingGame) to(InGame)",
+		"2024-01-02 03:04:05 [CHAT] mallory:    2.345 Info changing state from(CreatingGame) to(InGame)",
+		"   2.345 Warning Foo.cpp:1: changing state from(CreatingGame) to(InGame)",
+		"changing state from(CreatingGame) to(InGame)",
+	} {
+		assert.Assert(t, !factorio.IsReadyLine(line), line)
+	}
+}
+
+func TestLineLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"   0.001 2024-01-02 03:04:05; Factorio 2.0.28 (build 80000, linux64, headless)":     slog.LevelInfo,
+		"   1.234 Info ServerMultiplayerManager.cpp:123: Matching server connection resumed": slog.LevelInfo,
+		"   1.234 Verbose Something.cpp:1: detail":                                           slog.LevelDebug,
+		"  12.345 Warning AppManager.cpp:1: Mod 'foo' is outdated":                           slog.LevelWarn,
+		"1234.567 Error ServerMultiplayerManager.cpp:1: oops":                                slog.LevelError,
+		"2024-01-02 03:04:05 [CHAT] alice: 1.0 Error in chat":                                slog.LevelInfo,
+	}
+
+	for line, want := range tests {
+		assert.Equal(t, factorio.LineLevel(line), want, line)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package factoriotest provides helpers for writing Factorio files, such
+// as saves, for use in tests.
+package factoriotest
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// WriteSave writes a save to path containing the file world/name with
+// the provided contents, compressing them with zlib if compress is
+// true. Any missing parent directories are created.
+func WriteSave(path, name string, contents []byte, compress bool) error {
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(contents); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		contents = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // Why: Best effort.
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("world/" + name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(contents); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Header returns the start of a save header for a save made with the
+// provided version, which is enough for its version to be read.
+func Header(v factorio.Version) []byte {
+	var b []byte
+	for _, n := range []int{v.Major, v.Minor, v.Patch, 1} { // 1 is the build.
+		b = binary.LittleEndian.AppendUint16(b, uint16(n)) //nolint:gosec // Why: Versions fit in 16 bits.
+	}
+	return b
+}
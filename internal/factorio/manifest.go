@@ -0,0 +1,161 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestEntry is a single file in a Factorio install.
+type ManifestEntry struct {
+	// Path is the path of the file, relative to the root of the install.
+	Path string `json:"path"`
+
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the SHA256 sum of the file's contents.
+	SHA256 string `json:"sha256"`
+
+	// ModTime is the modification time of the file when it was
+	// installed. This is only set for manifests of an install on disk and
+	// is used to quickly detect modified files without hashing them.
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Manifest is a list of every regular file in a Factorio install.
+type Manifest []ManifestEntry
+
+// VerifyMode controls how thoroughly a manifest is verified.
+type VerifyMode int
+
+const (
+	// VerifyFast compares file sizes and modification times, only
+	// hashing files when no modification time was recorded.
+	VerifyFast VerifyMode = iota
+
+	// VerifyFull hashes every file.
+	VerifyFull
+)
+
+// ManifestProblem is a file in an install that does not match its
+// manifest.
+type ManifestProblem struct {
+	// Path is the path of the file, relative to the root of the install.
+	Path string
+
+	// Reason is a human readable description of the problem.
+	Reason string
+}
+
+// String returns a human readable representation of the problem.
+func (p ManifestProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Reason)
+}
+
+// FetchManifest downloads the provided Factorio version and returns a
+// manifest of its files, without extracting it anywhere. If progress is
+// not nil, download progress is reported to it.
+func FetchManifest(ctx context.Context, version, sha256sum string, progress ProgressReporter) (Manifest, error) {
+	var m Manifest
+	if err := readRelease(ctx, version, sha256sum, progress, func(header *tar.Header, path string, r io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			return nil
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(h, r)
+		if err != nil {
+			return err
+		}
+
+		m = append(m, ManifestEntry{Path: path, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Verify checks every file in the manifest relative to dir, returning
+// all files that are missing or do not match. Files in dir that are not
+// in the manifest (e.g., configuration files) are ignored.
+func (m Manifest) Verify(dir string, mode VerifyMode) ([]ManifestProblem, error) {
+	var problems []ManifestProblem
+	for i := range m {
+		e := &m[i]
+
+		reason, err := verifyEntry(filepath.Join(dir, e.Path), e, mode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify %s: %w", e.Path, err)
+		}
+		if reason != "" {
+			problems = append(problems, ManifestProblem{Path: e.Path, Reason: reason})
+		}
+	}
+
+	return problems, nil
+}
+
+// verifyEntry checks that the file at path matches the provided entry,
+// returning the reason it does not. An empty reason means it matches.
+func verifyEntry(path string, e *ManifestEntry, mode VerifyMode) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // Why: By design.
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "missing", nil
+		}
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck // Why: Best effort.
+
+	inf, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if inf.Size() != e.Size {
+		return fmt.Sprintf("size mismatch: expected %d, got %d", e.Size, inf.Size()), nil
+	}
+
+	if mode == VerifyFast && !e.ModTime.IsZero() {
+		if !inf.ModTime().Equal(e.ModTime) {
+			return fmt.Sprintf("modified at %s", inf.ModTime().Format(time.RFC3339)), nil
+		}
+		return "", nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != e.SHA256 {
+		return fmt.Sprintf("SHA256 mismatch: expected %s, got %s", e.SHA256, sum), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestManifestDetectsModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	contents := map[string]string{
+		"bin/x64/factorio":    "binary",
+		"data/base/info.json": "{}",
+		"data/changelog.txt":  "changes",
+	}
+
+	var m factorio.Manifest
+	for path, content := range contents {
+		sum := sha256.Sum256([]byte(content))
+		m = append(m, factorio.ManifestEntry{
+			Path:   path,
+			Size:   int64(len(content)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o750))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o600))
+	}
+
+	problems, err := m.Verify(dir, factorio.VerifyFull)
+	assert.NilError(t, err)
+	assert.Equal(t, len(problems), 0)
+
+	// Same size, different contents.
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "bin/x64/factorio"), []byte("BINARY"), 0o600))
+	assert.NilError(t, os.Remove(filepath.Join(dir, "data/changelog.txt")))
+
+	problems, err = m.Verify(dir, factorio.VerifyFull)
+	assert.NilError(t, err)
+	assert.Equal(t, len(problems), 2)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ModListFile is the name of the file in the mods directory that lists
+// every mod and whether it is enabled.
+const ModListFile = "mod-list.json"
+
+// ModsDir returns the directory mods are stored in, in the provided
+// data directory.
+func ModsDir(dataPath string) string {
+	return filepath.Join(dataPath, "mods")
+}
+
+// Mod is a mod installed in a data directory.
+type Mod struct {
+	Name string `json:"name"`
+
+	// Version is the version of the mod, if known. Mods that are part of
+	// the game, such as base, have no version.
+	Version string `json:"version,omitempty"`
+
+	// Enabled is true if the mod is loaded by the server.
+	Enabled bool `json:"enabled"`
+}
+
+// modList is the contents of mod-list.json.
+type modList struct {
+	Mods []struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	} `json:"mods"`
+}
+
+// ListMods returns the mods in the provided data directory, sorted by
+// name. Mods are read from mod-list.json, with their versions taken
+// from the names of the mod files (e.g., 'name_1.2.3.zip'), keeping the
+// newest if there are several. Mod files
+// not in mod-list.json are enabled, since that's what Factorio does
+// when it next starts.
+func ListMods(dataPath string) ([]Mod, error) {
+	dir := ModsDir(dataPath)
+	mods := make(map[string]*Mod)
+
+	b, err := os.ReadFile(filepath.Join(dir, ModListFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read mod list: %w", err)
+	}
+	if err == nil {
+		var l modList
+		if err := json.Unmarshal(b, &l); err != nil {
+			return nil, fmt.Errorf("failed to decode mod list: %w", err)
+		}
+		for _, m := range l.Mods {
+			mods[m.Name] = &Mod{Name: m.Name, Enabled: m.Enabled}
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list mods: %w", err)
+	}
+	for _, f := range files {
+		name, version, ok := parseModFileName(f.Name(), f.IsDir())
+		if !ok {
+			continue
+		}
+
+		m, ok := mods[name]
+		if !ok {
+			m = &Mod{Name: name, Enabled: true}
+			mods[name] = m
+		}
+		if newerModVersion(version, m.Version) {
+			m.Version = version
+		}
+	}
+
+	l := make([]Mod, 0, len(mods))
+	for _, m := range mods {
+		l = append(l, *m)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l, nil
+}
+
+// newerModVersion returns true if version is newer than current, which
+// is empty if no version is known yet. Factorio loads the newest version
+// of a mod when several are installed.
+func newerModVersion(version, current string) bool {
+	if current == "" {
+		return true
+	}
+
+	// Both were validated by parseModFileName.
+	v, _ := ParseVersion(version) //nolint:errcheck // Why: See above.
+	c, _ := ParseVersion(current) //nolint:errcheck // Why: See above.
+	return c.LessThan(v)
+}
+
+// parseModFileName returns the name and version of the mod stored in a
+// file or directory of the mods directory, e.g., 'name_1.2.3.zip'.
+func parseModFileName(file string, isDir bool) (name, version string, ok bool) {
+	if !isDir {
+		var isZip bool
+		file, isZip = strings.CutSuffix(file, ".zip")
+		if !isZip {
+			return "", "", false
+		}
+	}
+
+	i := strings.LastIndexByte(file, '_')
+	if i <= 0 {
+		return "", "", false
+	}
+	if _, err := ParseVersion(file[i+1:]); err != nil {
+		return "", "", false
+	}
+
+	return file[:i], file[i+1:], true
+}
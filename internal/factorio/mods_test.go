@@ -0,0 +1,55 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestListMods(t *testing.T) {
+	dir := t.TempDir()
+	modsDir := factorio.ModsDir(dir)
+	assert.NilError(t, os.MkdirAll(filepath.Join(modsDir, "unpacked_mod_0.1.0"), 0o750))
+	for _, name := range []string{
+		"even_distribution_2.0.2.zip", "disabled-mod_1.0.0.zip", "notes.txt",
+		// The newest version wins, even when it doesn't sort last.
+		"rail-signals_1.9.0.zip", "rail-signals_1.10.0.zip", "rail-signals_1.2.0.zip",
+	} {
+		assert.NilError(t, os.WriteFile(filepath.Join(modsDir, name), nil, 0o600))
+	}
+	assert.NilError(t, os.WriteFile(filepath.Join(modsDir, factorio.ModListFile), []byte(`{"mods": [
+		{"name": "base", "enabled": true},
+		{"name": "even_distribution", "enabled": true},
+		{"name": "disabled-mod", "enabled": false}
+	]}`), 0o600))
+
+	mods, err := factorio.ListMods(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, mods, []factorio.Mod{
+		{Name: "base", Enabled: true},
+		{Name: "disabled-mod", Version: "1.0.0", Enabled: false},
+		{Name: "even_distribution", Version: "2.0.2", Enabled: true},
+		{Name: "rail-signals", Version: "1.10.0", Enabled: true},
+		{Name: "unpacked_mod", Version: "0.1.0", Enabled: true},
+	})
+}
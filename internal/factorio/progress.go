@@ -0,0 +1,168 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"io"
+	"log/slog"
+	"time"
+)
+
+// progressInterval is the minimum amount of time between progress
+// reports while a download is in progress.
+const progressInterval = 100 * time.Millisecond
+
+// Progress is a snapshot of the progress of a download.
+type Progress struct {
+	// Version is the version of Factorio being downloaded.
+	Version string
+
+	// Downloaded is the number of bytes downloaded so far.
+	Downloaded int64
+
+	// Total is the total number of bytes to download, as reported by the
+	// server. If the server did not report a size, it is -1.
+	Total int64
+
+	// Elapsed is how long the download has been running for.
+	Elapsed time.Duration
+
+	// Done is true once the download has finished and been validated.
+	Done bool
+}
+
+// Rate returns the average download rate in bytes per second.
+func (p *Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Downloaded) / p.Elapsed.Seconds()
+}
+
+// Percent returns how much of the download has completed, from 0 to
+// 100. If the total size is unknown, -1 is returned.
+func (p *Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Downloaded) / float64(p.Total) * 100
+}
+
+// ETA returns the estimated time until the download finishes. If it
+// can't be estimated, zero is returned.
+func (p *Progress) ETA() time.Duration {
+	rate := p.Rate()
+	if p.Total <= 0 || rate <= 0 || p.Downloaded >= p.Total {
+		return 0
+	}
+	return time.Duration(float64(p.Total-p.Downloaded) / rate * float64(time.Second)).Round(time.Second)
+}
+
+// ProgressReporter receives progress updates while downloading
+// Factorio.
+type ProgressReporter interface {
+	// Report is called periodically while downloading and once more,
+	// with Done set, when the download has finished.
+	Report(p *Progress)
+}
+
+// progressReader is an io.Reader that reports how much has been read
+// from the underlying reader to a ProgressReporter.
+type progressReader struct {
+	r        io.Reader
+	reporter ProgressReporter
+
+	progress Progress
+	start    time.Time
+	last     time.Time
+}
+
+// newProgressReader returns a progressReader that reports progress of
+// reading r to reporter. If reporter is nil, progress is not reported.
+func newProgressReader(r io.Reader, reporter ProgressReporter, version string, total int64) *progressReader {
+	return &progressReader{
+		r:        r,
+		reporter: reporter,
+		progress: Progress{Version: version, Total: total},
+		start:    time.Now(),
+	}
+}
+
+// Read implements io.Reader.
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.progress.Downloaded += int64(n)
+
+	if now := time.Now(); now.Sub(pr.last) >= progressInterval {
+		pr.last = now
+		pr.report(false)
+	}
+
+	return n, err
+}
+
+// report sends the current progress to the reporter, if set.
+func (pr *progressReader) report(done bool) {
+	if pr.reporter == nil {
+		return
+	}
+
+	pr.progress.Elapsed = time.Since(pr.start)
+	pr.progress.Done = done
+	pr.reporter.Report(&pr.progress)
+}
+
+// LogProgressReporter is a ProgressReporter that emits progress as
+// structured log records.
+type LogProgressReporter struct {
+	log      *slog.Logger
+	interval time.Duration
+	last     time.Time
+}
+
+// NewLogProgressReporter returns a ProgressReporter that logs progress
+// to log, at most once every interval.
+func NewLogProgressReporter(log *slog.Logger, interval time.Duration) *LogProgressReporter {
+	return &LogProgressReporter{log: log, interval: interval}
+}
+
+// Report implements ProgressReporter.
+func (r *LogProgressReporter) Report(p *Progress) {
+	now := time.Now()
+	if !p.Done && now.Sub(r.last) < r.interval {
+		return
+	}
+	r.last = now
+
+	attrs := []any{
+		"version", p.Version,
+		"downloaded_bytes", p.Downloaded,
+		"total_bytes", p.Total,
+		"bytes_per_second", int64(p.Rate()),
+	}
+
+	if p.Done {
+		r.log.Info("Downloaded Factorio", append(attrs, "elapsed", p.Elapsed.Round(time.Millisecond))...)
+		return
+	}
+
+	if percent := p.Percent(); percent >= 0 {
+		attrs = append(attrs, "percent", int(percent), "eta", p.ETA())
+	}
+	r.log.Info("Downloading Factorio", attrs...)
+}
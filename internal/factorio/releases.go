@@ -31,20 +31,19 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/ulikunitz/xz"
 )
 
-// DownloadVersion downloads a Factorio version to the specified
-// directory. The downloaded version is validated against the SHA256 sum
-// on the remote, and extracted to the specified directory.
-func DownloadVersion(ctx context.Context, version, sha256sum, destDir string) error {
-	if _, err := os.Stat(destDir); err != nil {
-		return err
-	}
-
+// readRelease downloads the tarball for a Factorio version and calls fn
+// for every entry in it. The provided path is relative to the root of
+// the install, with the leading "factorio/" removed. Once every entry has
+// been read, the downloaded tarball is validated against the provided
+// SHA256 sum. If progress is not nil, download progress is reported to
+// it.
+func readRelease(ctx context.Context, version, sha256sum string, progress ProgressReporter,
+	fn func(header *tar.Header, path string, r io.Reader) error) error {
 	req, err := http.NewRequestWithContext(ctx,
-		"GET", fmt.Sprintf("https://factorio.com/get-download/%s/headless/linux64", version),
+		"GET", fmt.Sprintf("%s/get-download/%s/headless/linux64", baseURL, version),
 		http.NoBody)
 	if err != nil {
 		return err
@@ -57,15 +56,20 @@ func DownloadVersion(ctx context.Context, version, sha256sum, destDir string) er
 	}
 	defer resp.Body.Close() //nolint:errcheck // Why: Best effort.
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download Factorio %s: unexpected status %s", version, resp.Status)
+	}
+
+	pr := newProgressReader(resp.Body, progress, version, resp.ContentLength)
+
 	h := sha256.New()
 	// While extracting the file, also calculate the SHA256sum. This
 	// prevents needing to read the file twice.
-	xzr, err := xz.NewReader(bufio.NewReader(io.TeeReader(resp.Body, h)))
+	xzr, err := xz.NewReader(bufio.NewReader(io.TeeReader(pr, h)))
 	if err != nil {
 		return err
 	}
 
-	// Extract the tarball to the destination directory.
 	t := tar.NewReader(xzr)
 	for {
 		header, err := t.Next()
@@ -76,11 +80,44 @@ func DownloadVersion(ctx context.Context, version, sha256sum, destDir string) er
 			return err
 		}
 
-		fInf := header.FileInfo()
-
 		archiveFilePath := header.Name
 		// Remove factorio/ from the path.
 		archiveFilePath = filepath.Clean(strings.TrimPrefix(archiveFilePath, "factorio/"))
+		if !filepath.IsLocal(archiveFilePath) {
+			return fmt.Errorf("%s: illegal file path", archiveFilePath)
+		}
+
+		if err := fn(header, archiveFilePath, t); err != nil {
+			return err
+		}
+	}
+
+	// Validate the SHA256 sum.
+	hexSum := hex.EncodeToString(h.Sum(nil))
+	if hexSum != sha256sum {
+		return fmt.Errorf("SHA256 sum does not match: expected %s, got %s", sha256sum, hexSum)
+	}
+	pr.report(true)
+
+	return nil
+}
+
+// DownloadVersion downloads a Factorio version to the specified
+// directory. The downloaded version is validated against the SHA256 sum
+// on the remote, and extracted to the specified directory. A manifest of
+// the extracted files is returned, which can later be used to verify
+// the install. If progress is not nil, download progress is reported to
+// it.
+func DownloadVersion(ctx context.Context, version, sha256sum, destDir string,
+	progress ProgressReporter) (Manifest, error) {
+	if _, err := os.Stat(destDir); err != nil {
+		return nil, err
+	}
+
+	// Extract the tarball to the destination directory.
+	var m Manifest
+	if err := readRelease(ctx, version, sha256sum, progress, func(header *tar.Header, archiveFilePath string, r io.Reader) error {
+		fInf := header.FileInfo()
 
 		//nolint:gosec // Why: We're mitigating it below.
 		destpath := filepath.Join(destDir, archiveFilePath)
@@ -90,54 +127,63 @@ func DownloadVersion(ctx context.Context, version, sha256sum, destDir string) er
 
 		if header.Typeflag == tar.TypeDir {
 			// We JIT create them later.
-			continue
+			return nil
 		}
 
-		// Using a closure to ensure the file is closed after we're done
-		// since defer won't work in a loop.
-		if err := func() error {
-			if err := os.MkdirAll(filepath.Dir(destpath), 0o750); err != nil {
-				return err
-			}
-
-			// TODO(jaredallard): Use github.com/jaredallard/archives
-			//nolint:gosec // Why: acceptable
-			f, err := os.Create(destpath)
-			if err != nil {
-				return err
-			}
-			defer f.Close() //nolint:errcheck // Why: Best effort.
+		if err := os.MkdirAll(filepath.Dir(destpath), 0o750); err != nil {
+			return err
+		}
 
-			if err := os.Chmod(destpath, fInf.Mode()); err != nil {
-				return fmt.Errorf("failed to chmod file %s: %w", destpath, err)
-			}
+		// TODO(jaredallard): Use github.com/jaredallard/archives
+		//nolint:gosec // Why: acceptable
+		f, err := os.Create(destpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // Why: Best effort.
 
-			//nolint:gosec // Why: This is a >0.
-			fmt.Println(" ->", destpath, humanize.Bytes(uint64(header.Size)))
-			for {
-				_, err = io.CopyN(f, t, 4096)
-				if err != nil {
-					if errors.Is(err, io.EOF) {
-						err = nil
-					}
+		if err := os.Chmod(destpath, fInf.Mode()); err != nil {
+			return fmt.Errorf("failed to chmod file %s: %w", destpath, err)
+		}
 
-					break
+		// Hash the file as we write it to build the manifest.
+		h := sha256.New()
+		w := io.MultiWriter(f, h)
+		var n int64
+		for {
+			var written int64
+			written, err = io.CopyN(w, r, 4096)
+			n += written
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
 				}
+
+				break
 			}
+		}
+		if err != nil {
 			// Pass the error up the stack.
 			return err
-		}(); err != nil {
+		}
+
+		inf, err := f.Stat()
+		if err != nil {
 			return err
 		}
-	}
 
-	// Validate the SHA256 sum.
-	hexSum := hex.EncodeToString(h.Sum(nil))
-	if hexSum != sha256sum {
-		return fmt.Errorf("SHA256 sum does not match: expected %s, got %s", sha256sum, hexSum)
+		if header.Typeflag == tar.TypeReg {
+			m = append(m, ManifestEntry{
+				Path:    archiveFilePath,
+				Size:    n,
+				SHA256:  hex.EncodeToString(h.Sum(nil)),
+				ModTime: inf.ModTime(),
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	fmt.Println("Downloaded and validated Factorio version", version)
-
-	return nil
+	return m, nil
 }
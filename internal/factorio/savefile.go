@@ -0,0 +1,291 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// maxHeaderSize is the maximum amount of data read from a save to parse
+// its header. Headers are small, but are followed by the whole map.
+const maxHeaderSize = 1 << 20
+
+// headerFiles are the files in a save that start with its header, in
+// order of preference. Newer versions of Factorio write the header to
+// level-init.dat, while older ones only have level.dat (split into
+// level.dat0, level.dat1, etc. since 0.17).
+var headerFiles = []string{"level-init.dat", "level.dat0", "level.dat"}
+
+// SaveHeader is the header of a save, describing how it was made.
+//
+// The map seed and play time are not part of the header, they're only
+// stored in the map data that follows it, which can't be read without
+// fully deserializing the map. They are not available.
+type SaveHeader struct {
+	// Version is the version of Factorio the save was made with.
+	Version Version
+
+	// Build is the build number of Version.
+	Build int
+
+	// Campaign is the campaign the scenario is from, if any.
+	Campaign string
+
+	// Scenario is the name of the scenario the save was started from,
+	// e.g., 'freeplay'.
+	Scenario string
+
+	// BaseMod is the mod the scenario is from, e.g., 'base'.
+	BaseMod string
+
+	// Mods are the mods the save was made with. It is nil if they
+	// couldn't be read, which can happen with save formats that this
+	// parser doesn't know about.
+	Mods []Mod
+}
+
+// CanLoad returns true if version v of Factorio can load the save with
+// header h. Saves made with a newer version than v can't be loaded.
+func CanLoad(h *SaveHeader, v Version) bool {
+	return !v.LessThan(h.Version)
+}
+
+// ReadSaveHeader reads the header of the save at path.
+func ReadSaveHeader(path string) (*SaveHeader, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open save: %w", err)
+	}
+	defer zr.Close() //nolint:errcheck // Why: Best effort.
+
+	b, err := readHeaderData(&zr.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save %s: %w", path, err)
+	}
+
+	h, err := parseSaveHeader(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse save header of %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// readHeaderData returns the start of the file in the save containing
+// its header, decompressing it if needed.
+func readHeaderData(zr *zip.Reader) ([]byte, error) {
+	var f *zip.File
+	for _, name := range headerFiles {
+		for _, zf := range zr.File {
+			// Files are stored in a directory named after the save.
+			if path.Base(zf.Name) == name {
+				f = zf
+				break
+			}
+		}
+		if f != nil {
+			break
+		}
+	}
+	if f == nil {
+		return nil, errors.New("no level data found, is it a Factorio save?")
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() //nolint:errcheck // Why: Best effort.
+
+	b, err := io.ReadAll(io.LimitReader(rc, maxHeaderSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// Since 0.18, level data is compressed with zlib.
+	if len(b) >= 2 && b[0] == 0x78 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0 {
+		zlr, err := zlib.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", f.Name, err)
+		}
+		defer zlr.Close() //nolint:errcheck // Why: Best effort.
+
+		// The data is truncated, so an unexpected EOF is expected once
+		// enough has been read.
+		b, err = io.ReadAll(io.LimitReader(zlr, maxHeaderSize))
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("failed to decompress %s: %w", f.Name, err)
+		}
+	}
+
+	return b, nil
+}
+
+// parseSaveHeader parses a save header. Only the version is required,
+// since it has been stored the same way in every version of Factorio.
+// The rest is read on a best-effort basis.
+func parseSaveHeader(b []byte) (*SaveHeader, error) {
+	r := &headerReader{b: b}
+
+	var v [4]uint16
+	for i := range v {
+		v[i] = r.uint16()
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to read version: %w", r.err)
+	}
+
+	h := &SaveHeader{
+		Version: Version{Major: int(v[0]), Minor: int(v[1]), Patch: int(v[2])},
+		Build:   int(v[3]),
+	}
+
+	// Unknown, but always present.
+	r.uint8()
+
+	// From here on, parsing stops at the first problem, keeping what has
+	// been read so far.
+	campaign, scenario, baseMod := r.string(), r.string(), r.string()
+	if r.err != nil {
+		return h, nil
+	}
+	h.Campaign, h.Scenario, h.BaseMod = campaign, scenario, baseMod
+
+	r.uint8()  // difficulty
+	r.uint8()  // finished
+	r.uint8()  // player won
+	r.string() // next level
+	r.uint8()  // can continue
+	r.uint8()  // finished but continuing
+	r.uint8()  // saving replay
+	r.uint8()  // allow non-admin debug options
+	r.uint8()  // loaded from major
+	r.uint8()  // loaded from minor
+	r.uint8()  // loaded from patch
+	r.uint16() // loaded from build
+	r.uint8()  // allowed commands
+
+	count := r.optimizedUint32()
+	if r.err != nil || count > maxMods {
+		return h, nil
+	}
+
+	mods := make([]Mod, 0, count)
+	for range count {
+		name := r.string()
+		mv := Version{Major: int(r.optimizedUint16()), Minor: int(r.optimizedUint16()), Patch: int(r.optimizedUint16())}
+		r.uint32() // CRC
+		if r.err != nil || !validModName(name) {
+			return h, nil
+		}
+		mods = append(mods, Mod{Name: name, Version: mv.String(), Enabled: true})
+	}
+	h.Mods = mods
+
+	return h, nil
+}
+
+// maxMods is the largest number of mods believed to be in a save. More
+// than this means the header wasn't parsed correctly.
+const maxMods = 10000
+
+// validModName returns true if name looks like the name of a mod, to
+// detect headers that weren't parsed correctly.
+func validModName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// headerReader reads the little-endian values of a save header. Once a
+// read fails, err is set and every further read returns zero.
+type headerReader struct {
+	b   []byte
+	err error
+}
+
+// next returns the next n bytes.
+func (r *headerReader) next(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.b) < n {
+		r.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+// uint8 reads a byte.
+func (r *headerReader) uint8() uint8 {
+	return r.next(1)[0]
+}
+
+// uint16 reads a 16-bit integer.
+func (r *headerReader) uint16() uint16 {
+	return binary.LittleEndian.Uint16(r.next(2))
+}
+
+// uint32 reads a 32-bit integer.
+func (r *headerReader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.next(4))
+}
+
+// optimizedUint16 reads a space optimized 16-bit integer, which is a
+// byte, unless it is 0xff, in which case a 16-bit integer follows.
+func (r *headerReader) optimizedUint16() uint16 {
+	if v := r.uint8(); v != 0xff {
+		return uint16(v)
+	}
+	return r.uint16()
+}
+
+// optimizedUint32 reads a space optimized 32-bit integer, which is a
+// byte, unless it is 0xff, in which case a 32-bit integer follows.
+func (r *headerReader) optimizedUint32() uint32 {
+	if v := r.uint8(); v != 0xff {
+		return uint32(v)
+	}
+	return r.uint32()
+}
+
+// string reads a string prefixed by its length as a space optimized
+// 32-bit integer.
+func (r *headerReader) string() string {
+	n := r.optimizedUint32()
+	if r.err != nil || uint64(n) > uint64(len(r.b)) {
+		r.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	return string(r.next(int(n)))
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/factorio/factoriotest"
+	"gotest.tools/v3/assert"
+)
+
+// headerWriter builds a save header for tests.
+type headerWriter struct {
+	bytes.Buffer
+}
+
+func (w *headerWriter) uint16s(vs ...uint16) {
+	for _, v := range vs {
+		binary.Write(w, binary.LittleEndian, v) //nolint:errcheck // Why: Can't fail.
+	}
+}
+
+func (w *headerWriter) str(s string) {
+	w.WriteByte(byte(len(s)))
+	w.WriteString(s)
+}
+
+// writeSave writes a save containing the file name with the provided
+// contents, compressing it with zlib if compress is true.
+func writeSave(t *testing.T, name string, contents []byte, compress bool) string {
+	path := filepath.Join(t.TempDir(), "world.zip")
+	assert.NilError(t, factoriotest.WriteSave(path, name, contents, compress))
+	return path
+}
+
+func TestCanLoad(t *testing.T) {
+	h := &factorio.SaveHeader{Version: factorio.Version{Major: 2, Patch: 28}}
+	assert.Assert(t, factorio.CanLoad(h, factorio.Version{Major: 2, Patch: 28}))
+	assert.Assert(t, factorio.CanLoad(h, factorio.Version{Major: 2, Patch: 30}))
+	assert.Assert(t, !factorio.CanLoad(h, factorio.Version{Major: 2, Patch: 20}))
+	assert.Assert(t, !factorio.CanLoad(h, factorio.Version{Major: 1, Minor: 1, Patch: 110}))
+}
+
+func TestReadSaveHeader(t *testing.T) {
+	var w headerWriter
+	w.uint16s(2, 0, 28, 12345)
+	w.WriteByte(0)
+	w.str("")
+	w.str("freeplay")
+	w.str("base")
+	w.Write(make([]byte, 8)) // difficulty, flags and next level
+	w.Write([]byte{2, 0, 28})
+	w.uint16s(12345)
+	w.WriteByte(1)
+	w.WriteByte(2)
+	for _, name := range []string{"base", "even_distribution"} {
+		w.str(name)
+		w.Write([]byte{2, 0, 2})
+		w.Write(make([]byte, 4))
+	}
+	w.WriteString("map data follows")
+
+	h, err := factorio.ReadSaveHeader(writeSave(t, "level-init.dat", w.Bytes(), true))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, h, &factorio.SaveHeader{
+		Version:  factorio.Version{Major: 2, Minor: 0, Patch: 28},
+		Build:    12345,
+		Scenario: "freeplay",
+		BaseMod:  "base",
+		Mods: []factorio.Mod{
+			{Name: "base", Version: "2.0.2", Enabled: true},
+			{Name: "even_distribution", Version: "2.0.2", Enabled: true},
+		},
+	})
+}
+
+func TestReadSaveHeaderOnlyRequiresVersion(t *testing.T) {
+	var w headerWriter
+	w.uint16s(1, 1, 110, 5678)
+	w.WriteByte(0)
+	w.WriteByte(200) // A string longer than the data.
+
+	h, err := factorio.ReadSaveHeader(writeSave(t, "level.dat0", w.Bytes(), false))
+	assert.NilError(t, err)
+	assert.Equal(t, h.Version, factorio.Version{Major: 1, Minor: 1, Patch: 110})
+	assert.Assert(t, h.Mods == nil)
+}
+
+func TestReadSaveHeaderRejectsOtherZips(t *testing.T) {
+	_, err := factorio.ReadSaveHeader(writeSave(t, "readme.txt", []byte("hi"), false))
+	assert.ErrorContains(t, err, "no level data found")
+}
+
+// fixtureHeader is the expected header of a save fixture.
+type fixtureHeader struct {
+	Version  string `json:"version"`
+	Build    int    `json:"build"`
+	Scenario string `json:"scenario"`
+	BaseMod  string `json:"base_mod"`
+	Mods     []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"mods"`
+}
+
+func TestReadSaveHeaderFixtures(t *testing.T) {
+	saves, err := filepath.Glob(filepath.Join("testdata", "saves", "*.zip"))
+	assert.NilError(t, err)
+	if len(saves) == 0 {
+		t.Skip("no save fixtures in testdata/saves")
+	}
+
+	for _, save := range saves {
+		t.Run(filepath.Base(save), func(t *testing.T) {
+			b, err := os.ReadFile(strings.TrimSuffix(save, ".zip") + ".json")
+			assert.NilError(t, err)
+
+			var want fixtureHeader
+			assert.NilError(t, json.Unmarshal(b, &want))
+
+			h, err := factorio.ReadSaveHeader(save)
+			assert.NilError(t, err)
+			assert.Equal(t, h.Version.String(), want.Version)
+			assert.Equal(t, h.Build, want.Build)
+			assert.Equal(t, h.Scenario, want.Scenario)
+			assert.Equal(t, h.BaseMod, want.BaseMod)
+
+			if want.Mods == nil {
+				assert.Assert(t, h.Mods == nil)
+				return
+			}
+			assert.Equal(t, len(h.Mods), len(want.Mods))
+			for i, m := range want.Mods {
+				assert.Equal(t, h.Mods[i].Name, m.Name)
+				assert.Equal(t, h.Mods[i].Version, m.Version)
+			}
+		})
+	}
+}
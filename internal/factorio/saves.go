@@ -18,35 +18,99 @@
 package factorio
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-
-	"github.com/jaredallard/factorio-docker/internal/config"
+	"sort"
+	"strings"
+	"time"
 )
 
-// GenerateSave generates a new Factorio save.
-func GenerateSave(cfg *config.Config, execPath, saveName string) error {
+// SavesDir returns the directory saves are stored in, in the provided
+// data directory.
+func SavesDir(dataPath string) string {
+	return filepath.Join(dataPath, "saves")
+}
+
+// Save is a save file in a data directory.
+type Save struct {
+	// Path is the path to the save.
+	Path string
+
+	// ModTime is when the save was last modified.
+	ModTime time.Time
+}
+
+// Name returns the name of the save, without its extension.
+func (s *Save) Name() string {
+	return strings.TrimSuffix(filepath.Base(s.Path), ".zip")
+}
+
+// ListSaves returns the saves in the provided data directory, most
+// recently modified first.
+func ListSaves(dataPath string) ([]Save, error) {
+	files, err := os.ReadDir(SavesDir(dataPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list saves: %w", err)
+	}
+
+	var saves []Save
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".zip" {
+			continue
+		}
+
+		inf, err := f.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat save %s: %w", f.Name(), err)
+		}
+		saves = append(saves, Save{Path: filepath.Join(SavesDir(dataPath), f.Name()), ModTime: inf.ModTime()})
+	}
+
+	sort.SliceStable(saves, func(i, j int) bool { return saves[i].ModTime.After(saves[j].ModTime) })
+	return saves, nil
+}
+
+// LatestSave returns the path to the most recently modified save in the
+// provided data directory, which is the one loaded by the server. If
+// there are no saves, an empty string is returned.
+func LatestSave(dataPath string) (string, error) {
+	saves, err := ListSaves(dataPath)
+	if err != nil || len(saves) == 0 {
+		return "", err
+	}
+	return saves[0].Path, nil
+}
+
+// GenerateSave generates a new Factorio save in the provided data
+// directory, writing Factorio's output to stdout and stderr.
+func GenerateSave(dataPath, execPath, saveName string, stdout, stderr io.Writer) error {
 	args := [...]string{
 		execPath,
-		"--create", filepath.Join(cfg.ServerDataPath, "saves", saveName) + ".zip",
-		"--map-gen-settings", filepath.Join(cfg.ServerDataPath, "map-gen-settings.json"),
-		"--map-settings", filepath.Join(cfg.ServerDataPath, "map-settings.json"),
+		"--create", filepath.Join(SavesDir(dataPath), saveName) + ".zip",
+		"--map-gen-settings", filepath.Join(dataPath, "map-gen-settings.json"),
+		"--map-settings", filepath.Join(dataPath, "map-settings.json"),
 	}
 
 	//nolint:gosec // Why: created above
 	cmd := exec.Command(args[0], args[1:]...)
-	cmd.Dir = cfg.ServerDataPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Dir = dataPath
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	return cmd.Run()
 }
 
-// GenerateDefaultSave creates the default save file.
-func GenerateDefaultSave(cfg *config.Config, execPath string) error {
+// GenerateDefaultSave creates the default save file in the provided
+// data directory, if it doesn't contain any saves, writing Factorio's
+// output to stdout and stderr.
+func GenerateDefaultSave(dataPath, execPath string, stdout, stderr io.Writer) error {
 	// If there's no save found, create one.
-	savesDir := filepath.Join(cfg.ServerDataPath, "saves")
-	files, err := os.ReadDir(savesDir)
+	files, err := os.ReadDir(SavesDir(dataPath))
 	if err != nil {
 		return err
 	}
@@ -63,5 +127,5 @@ func GenerateDefaultSave(cfg *config.Config, execPath string) error {
 		return nil
 	}
 
-	return GenerateSave(cfg, execPath, "_autosave1")
+	return GenerateSave(dataPath, execPath, "_autosave1", stdout, stderr)
 }
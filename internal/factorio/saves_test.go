@@ -0,0 +1,41 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerateDefaultSaveWritesOutput(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(factorio.SavesDir(dir), 0o750))
+
+	execPath := filepath.Join(dir, "factorio")
+	assert.NilError(t, os.WriteFile(execPath, []byte("#!/bin/sh\necho generating map\necho warning >&2\n"), 0o700)) //nolint:gosec // Why: Test.
+
+	var stdout, stderr bytes.Buffer
+	assert.NilError(t, factorio.GenerateDefaultSave(dir, execPath, &stdout, &stderr))
+	assert.Equal(t, stdout.String(), "generating map\n")
+	assert.Equal(t, stderr.String(), "warning\n")
+}
@@ -0,0 +1,324 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is a Factorio version number, e.g., 2.0.28. Factorio always
+// uses three-part versions without any pre-release or build metadata.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a three-part Factorio version, e.g., "2.0.28".
+func ParseVersion(s string) (Version, error) {
+	parts, err := parseVersionParts(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected major.minor.patch", s)
+	}
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, nil
+}
+
+// parseVersionParts parses a version with one to three numeric parts,
+// returning each part.
+func parseVersionParts(s string) ([]int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("invalid version: empty string")
+	}
+
+	rawParts := strings.Split(s, ".")
+	if len(rawParts) > 3 {
+		return nil, fmt.Errorf("invalid version %q: too many parts", s)
+	}
+
+	parts := make([]int, 0, len(rawParts))
+	for _, p := range rawParts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q: %q is not a number", s, p)
+		}
+		parts = append(parts, n)
+	}
+
+	return parts, nil
+}
+
+// String returns the string representation of the version.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1 if v is older than o, 1 if v is newer than o and
+// 0 if they are the same version.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return cmpInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return cmpInt(v.Minor, o.Minor)
+	default:
+		return cmpInt(v.Patch, o.Patch)
+	}
+}
+
+// LessThan returns true if v is older than o.
+func (v Version) LessThan(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// cmpInt compares two integers, returning -1, 0 or 1.
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SortVersions sorts the provided versions from oldest to newest.
+func SortVersions(versions []Version) {
+	slices.SortFunc(versions, Version.Compare)
+}
+
+// operator is a comparison operator used in a version constraint.
+type operator string
+
+const (
+	opEqual        operator = "="
+	opNotEqual     operator = "!="
+	opGreater      operator = ">"
+	opGreaterEqual operator = ">="
+	opLess         operator = "<"
+	opLessEqual    operator = "<="
+	opTilde        operator = "~"
+	opCaret        operator = "^"
+)
+
+// operators contains all supported operators. Longer operators are
+// listed first so that prefix matching picks them over their shorter
+// counterparts (e.g., ">=" over ">").
+var operators = []operator{
+	opNotEqual, opGreaterEqual, opLessEqual,
+	opEqual, opGreater, opLess, opTilde, opCaret,
+}
+
+// comparison is a single comparison in a constraint, e.g., ">=1.1.100".
+type comparison struct {
+	op operator
+
+	// version is the version being compared against, with missing parts
+	// set to zero.
+	version Version
+
+	// parts is how many parts of the version were provided, used to
+	// treat partial versions as wildcards (e.g., "2.0" is "2.0.x").
+	parts int
+}
+
+// matches returns true if the provided version satisfies the
+// comparison.
+func (c *comparison) matches(v Version) bool {
+	lower, upper := c.bounds()
+
+	switch c.op {
+	case opEqual, opTilde, opCaret:
+		return !v.LessThan(lower) && v.LessThan(upper)
+	case opNotEqual:
+		return v.LessThan(lower) || !v.LessThan(upper)
+	case opGreater:
+		return !v.LessThan(upper)
+	case opGreaterEqual:
+		return !v.LessThan(lower)
+	case opLess:
+		return v.LessThan(lower)
+	case opLessEqual:
+		return v.LessThan(upper)
+	default:
+		return false
+	}
+}
+
+// bounds returns the inclusive lower and exclusive upper bound of the
+// versions described by the comparison's version and operator.
+func (c *comparison) bounds() (lower, upper Version) {
+	lower = c.version
+
+	// The number of leading parts that must stay the same for a version
+	// to fall within the range.
+	fixed := c.parts
+	switch c.op {
+	case opTilde:
+		// ~2.0.5 allows patch updates, ~2 allows minor updates.
+		fixed = min(c.parts, 2)
+	case opCaret:
+		// ^1.1 allows minor and patch updates. Leading zeros stay fixed
+		// too, since 0.x releases may break compatibility: ^0.17 only
+		// allows patch updates.
+		parts := [...]int{lower.Major, lower.Minor, lower.Patch}
+		fixed = 1
+		for fixed < c.parts && parts[fixed-1] == 0 {
+			fixed++
+		}
+	}
+
+	switch fixed {
+	case 1:
+		upper = Version{Major: lower.Major + 1}
+	case 2:
+		upper = Version{Major: lower.Major, Minor: lower.Minor + 1}
+	default:
+		upper = Version{Major: lower.Major, Minor: lower.Minor, Patch: lower.Patch + 1}
+	}
+
+	return lower, upper
+}
+
+// Constraint is a set of version requirements, e.g., "~2.0" or
+// ">=1.1.100 <2.0". Space separated comparisons must all match, while
+// "||" can be used to provide alternatives.
+//
+// Supported operators are =, !=, >, >=, <, <=, ~ (patch updates, or
+// minor updates when only a major version is given) and ^ (minor and
+// patch updates, or only patch updates for 0.x versions). Versions
+// may omit the minor and patch parts, in which case they are treated
+// as wildcards, e.g., "2.0" matches any 2.0.x release.
+type Constraint struct {
+	raw    string
+	groups [][]comparison
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+
+	for _, rawGroup := range strings.Split(s, "||") {
+		fields := strings.Fields(rawGroup)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty comparison", s)
+		}
+
+		group := make([]comparison, 0, len(fields))
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+
+			// Support operators separated from the version by a space, e.g.,
+			// ">= 1.1".
+			if slices.Contains(operators, operator(field)) && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+
+			cmp, err := parseComparison(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			group = append(group, cmp)
+		}
+
+		c.groups = append(c.groups, group)
+	}
+
+	return c, nil
+}
+
+// parseComparison parses a single comparison, e.g., ">=1.1.100".
+func parseComparison(s string) (comparison, error) {
+	op := opEqual
+	for _, o := range operators {
+		if strings.HasPrefix(s, string(o)) {
+			op = o
+			s = strings.TrimPrefix(s, string(o))
+			break
+		}
+	}
+
+	// Allow the "x" and "*" wildcards, e.g., "2.0.x".
+	s = strings.TrimSuffix(strings.TrimSuffix(s, ".x"), ".*")
+
+	parts, err := parseVersionParts(s)
+	if err != nil {
+		return comparison{}, err
+	}
+
+	var v Version
+	v.Major = parts[0]
+	if len(parts) > 1 {
+		v.Minor = parts[1]
+	}
+	if len(parts) > 2 {
+		v.Patch = parts[2]
+	}
+
+	return comparison{op: op, version: v, parts: len(parts)}, nil
+}
+
+// Check returns true if the provided version satisfies the constraint.
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		matched := true
+		for i := range group {
+			if !group[i].matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Latest returns the newest version out of the provided versions that
+// satisfies the constraint. If none match, false is returned.
+func (c *Constraint) Latest(versions []Version) (Version, bool) {
+	var latest Version
+	var found bool
+	for _, v := range versions {
+		if !c.Check(v) {
+			continue
+		}
+
+		if !found || latest.LessThan(v) {
+			latest = v
+			found = true
+		}
+	}
+
+	return latest, found
+}
+
+// String returns the constraint as it was originally provided.
+func (c *Constraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package factorio_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.0.28", "2.0.28", 0},
+		{"1.1.110", "2.0.0", -1},
+		{"2.0.9", "2.0.10", -1},
+		{"2.1.0", "2.0.99", 1},
+	}
+
+	for _, tt := range tests {
+		a, err := factorio.ParseVersion(tt.a)
+		assert.NilError(t, err)
+		b, err := factorio.ParseVersion(tt.b)
+		assert.NilError(t, err)
+
+		assert.Equal(t, a.Compare(b), tt.want, "%s <=> %s", tt.a, tt.b)
+	}
+}
+
+func TestRejectsInvalidVersions(t *testing.T) {
+	for _, v := range []string{"", "2.0", "2.0.x", "1.2.3.4", "stable", "2.0.-1"} {
+		_, err := factorio.ParseVersion(v)
+		assert.Assert(t, err != nil, "expected %q to be invalid", v)
+	}
+}
+
+func TestConstraintsMatchVersions(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{"~2.0", []string{"2.0.0", "2.0.28"}, []string{"1.1.110", "2.1.0"}},
+		{"~2", []string{"2.0.0", "2.5.1"}, []string{"3.0.0"}},
+		{"~2.0.5", []string{"2.0.5", "2.0.30"}, []string{"2.0.4", "2.1.0"}},
+		{"^1.1", []string{"1.1.0", "1.9.9"}, []string{"1.0.9", "2.0.0"}},
+		{"^0.17", []string{"0.17.0", "0.17.79"}, []string{"0.16.51", "0.18.0", "1.0.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.1.0"}},
+		{"^0", []string{"0.0.1", "0.18.47"}, []string{"1.0.0"}},
+		{">=1.1.100 <2.0", []string{"1.1.100", "1.1.110"}, []string{"1.1.99", "2.0.0"}},
+		{">= 1.1.100, <2.0", nil, nil},
+		{"2.0", []string{"2.0.0", "2.0.28"}, []string{"2.1.0"}},
+		{"2.0.x", []string{"2.0.7"}, []string{"1.0.0"}},
+		{"<=2.0", []string{"2.0.99"}, []string{"2.1.0"}},
+		{">2.0", []string{"2.1.0"}, []string{"2.0.99"}},
+		{"!=2.0.1", []string{"2.0.0", "2.0.2"}, []string{"2.0.1"}},
+		{"~1.1 || ~2.0", []string{"1.1.110", "2.0.1"}, []string{"1.0.0", "3.0.0"}},
+	}
+
+	for _, tt := range tests {
+		c, err := factorio.ParseConstraint(tt.constraint)
+		if tt.matches == nil && tt.rejects == nil {
+			assert.Assert(t, err != nil, "expected constraint %q to be invalid", tt.constraint)
+			continue
+		}
+		assert.NilError(t, err)
+
+		for _, raw := range tt.matches {
+			v, err := factorio.ParseVersion(raw)
+			assert.NilError(t, err)
+			assert.Assert(t, c.Check(v), "expected %q to match %q", tt.constraint, raw)
+		}
+		for _, raw := range tt.rejects {
+			v, err := factorio.ParseVersion(raw)
+			assert.NilError(t, err)
+			assert.Assert(t, !c.Check(v), "expected %q to reject %q", tt.constraint, raw)
+		}
+	}
+}
+
+func TestCanListReleases(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/download/sha256sums/")
+		fmt.Fprintln(w, "aaaa  factorio_alpha_x64_2.0.28.tar.xz")
+		fmt.Fprintln(w, "bbbb  factorio-headless_linux_2.0.28.tar.xz")
+		fmt.Fprintln(w, "cccc  factorio_headless_x64_1.1.110.tar.xz")
+		fmt.Fprintln(w, "dddd  factorio-headless_linux_1.1.110.tar.xz")
+		fmt.Fprintln(w, "eeee  factorio_headless_x64_2.0.9.tar.xz")
+	}))
+	defer srv.Close()
+	defer factorio.SetBaseURL(srv.URL)()
+
+	rels, err := factorio.GetReleases()
+	assert.NilError(t, err)
+	assert.Equal(t, len(rels), 3)
+	assert.Equal(t, rels[0].Version.String(), "1.1.110")
+	assert.Equal(t, rels[0].SHA256, "cccc")
+	assert.Equal(t, rels[1].Version.String(), "2.0.9")
+	assert.Equal(t, rels[2].Version.String(), "2.0.28")
+
+	c, err := factorio.ParseConstraint("~2.0")
+	assert.NilError(t, err)
+
+	versions := make([]factorio.Version, 0, len(rels))
+	for _, rel := range rels {
+		versions = append(versions, rel.Version)
+	}
+	latest, ok := c.Latest(versions)
+	assert.Assert(t, ok)
+	assert.Equal(t, latest.String(), "2.0.28")
+}
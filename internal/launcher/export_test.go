@@ -0,0 +1,55 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"testing"
+	"time"
+)
+
+// UseWhitelist is exported for testing.
+var UseWhitelist = useWhitelist
+
+// SetupConfig is exported for testing.
+var SetupConfig = setupConfig
+
+// NewTestServer returns a Server that uses the RCON server at addr.
+func NewTestServer(addr, password string) *Server {
+	return &Server{rconAddr: addr, rconPassword: password}
+}
+
+// CheckPorts is exported for testing.
+var CheckPorts = checkPorts
+
+// RunVanilla is exported for testing.
+var RunVanilla = runVanilla
+
+// NotifyExit is exported for testing.
+var NotifyExit = notifyExit
+
+// NextWarning is exported for testing.
+var NextWarning = nextWarning
+
+// SetUpgradeTimings overrides how long the upgrade logic waits between
+// checks for online players and attempts to save the server, until the
+// test finishes.
+func SetUpgradeTimings(t *testing.T, emptyCheck, saveRetry time.Duration) {
+	oldEmptyCheck, oldSaveRetry := emptyCheckInterval, saveRetryDelay
+	t.Cleanup(func() { emptyCheckInterval, saveRetryDelay = oldEmptyCheck, oldSaveRetry })
+	emptyCheckInterval, saveRetryDelay = emptyCheck, saveRetry
+}
@@ -15,7 +15,7 @@
 //
 // SPDX-License-Identifier: AGPL-3.0
 
-// Package launcher runs Factorio or wrappers, such as Factocord.
+// Package launcher configures and runs the Factorio server.
 package launcher
 
 import (
@@ -26,21 +26,31 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/jaredallard/factorio-docker/internal/config"
 	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"github.com/jaredallard/factorio-docker/internal/serverlog"
 	"gopkg.in/ini.v1"
 )
 
 //go:embed embed/factorio-config.ini
 var defaultFactorioConfig []byte
 
-// setupConfig ensures that the Factorio server has a configuration file
-// and that it points to our data directory.
-func setupConfig(cfg *config.Config) error {
+// setupConfig ensures that the Factorio server has a configuration file,
+// that it points to our data directory and that it contains the
+// configured overrides.
+func setupConfig(log *slog.Logger, cfg *config.Config) error {
 	configPath := filepath.Join(cfg.InstallPath, "config", "config.ini")
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	_, err := os.Stat(configPath)
+	if os.IsNotExist(err) || cfg.INIReset {
+		if cfg.INIReset && err == nil {
+			log.Info("Resetting config.ini to defaults", "path", configPath)
+		}
+
 		if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
 			return fmt.Errorf("failed to create config directory: %w", err)
 		}
@@ -59,19 +69,15 @@ func setupConfig(cfg *config.Config) error {
 		return fmt.Errorf("failed to load config file: %w", err)
 	}
 
-	// Point the server to the correct data path.
-	if !conf.HasSection("path") {
-		if _, err := conf.NewSection("path"); err != nil {
-			return fmt.Errorf("failed to create path section: %w", err)
+	// Section and Key create the section and key if they don't exist.
+	for _, section := range cfg.INISections() {
+		for k, v := range cfg.INI[section] {
+			conf.Section(section).Key(k).SetValue(v)
 		}
 	}
-	if !conf.Section("path").HasKey("write-data") {
-		if _, err := conf.Section("path").NewKey("write-data", cfg.ServerDataPath); err != nil {
-			return fmt.Errorf("failed to create write-data key: %w", err)
-		}
-	} else {
-		conf.Section("path").Key("write-data").SetValue(cfg.ServerDataPath)
-	}
+
+	// Point the server to the correct data path.
+	conf.Section("path").Key("write-data").SetValue(cfg.ServerDataPath)
 
 	if err := conf.SaveTo(configPath); err != nil {
 		return fmt.Errorf("failed to save config file: %w", err)
@@ -148,9 +154,43 @@ func installDefaultFiles(log *slog.Logger, cfg *config.Config) error {
 	return nil
 }
 
-// Launch starts a Factorio server based on the provided configuration.
-func Launch(ctx context.Context, log *slog.Logger, cfg *config.Config) error {
-	if err := setupConfig(cfg); err != nil {
+// Hooks are called by the launcher as the server changes state. Any
+// hook may be nil.
+type Hooks struct {
+	// OnReady is called once the server has loaded a save and is
+	// accepting players.
+	OnReady func(srv *Server)
+
+	// OnConsoleEvent is called for every event, such as a chat message,
+	// output to the server console.
+	OnConsoleEvent func(ev factorio.ConsoleEvent)
+}
+
+// checkSave warns if the save the server will load was made with a
+// newer version of Factorio than the installed one, since the server
+// will fail to load it.
+func checkSave(log *slog.Logger, cfg *config.Config) {
+	path, err := factorio.LatestSave(cfg.ServerDataPath)
+	if err != nil || path == "" {
+		return
+	}
+
+	h, err := factorio.ReadSaveHeader(path)
+	if err != nil {
+		log.Warn("Failed to read save header", "err", err)
+		return
+	}
+
+	if v, err := factorio.ParseVersion(cfg.Version); err == nil && !factorio.CanLoad(h, v) {
+		log.Warn("Save was made with a newer version of Factorio and will fail to load",
+			"path", path, "save_version", h.Version.String(), "version", cfg.Version)
+	}
+}
+
+// Launch starts a Factorio server based on the provided configuration,
+// sending events about it to n.
+func Launch(ctx context.Context, log *slog.Logger, cfg *config.Config, n *notify.Notifier, hooks Hooks) error {
+	if err := setupConfig(log, cfg); err != nil {
 		return fmt.Errorf("failed to setup config: %w", err)
 	}
 
@@ -158,30 +198,91 @@ func Launch(ctx context.Context, log *slog.Logger, cfg *config.Config) error {
 		return fmt.Errorf("failed to install default files: %w", err)
 	}
 
+	if err := UpdateServerSettings(cfg); err != nil {
+		return fmt.Errorf("failed to update server settings: %w", err)
+	}
+
+	if err := writePlayers(ctx, log, cfg); err != nil {
+		return fmt.Errorf("failed to write player lists: %w", err)
+	}
+
+	var out *serverlog.Writer
+	if cfg.Logs.Enabled {
+		logDir := serverlog.Dir(cfg.ServerDataPath)
+		opts := serverlog.Options{MaxSize: cfg.Logs.MaxSize, MaxAge: cfg.Logs.MaxAge, MaxBackups: cfg.Logs.MaxBackups}
+		if err := serverlog.RotateConsoleLog(logDir, opts); err != nil {
+			return err
+		}
+
+		var err error
+		out, err = serverlog.Open(logDir, opts)
+		if err != nil {
+			return err
+		}
+		defer out.Close() //nolint:errcheck // Why: Best effort.
+	}
+
 	execPath := filepath.Join(cfg.InstallPath, "bin", "x64", "factorio")
 
-	if err := factorio.GenerateDefaultSave(cfg, execPath); err != nil {
+	// Output from generating the map is handled like the server's, so
+	// that it's logged in the same format and written to the server log.
+	output := newGameOutput(log, out)
+	genStdout, genStderr := output.stdout(ctx, nil), output.stderr(ctx)
+	err := factorio.GenerateDefaultSave(cfg.ServerDataPath, execPath, genStdout, genStderr)
+	genStdout.Flush()
+	genStderr.Flush()
+	if err != nil {
 		return fmt.Errorf("failed to generate default save: %w", err)
 	}
 
+	checkSave(log, cfg)
+
+	if err := checkPorts(cfg); err != nil {
+		return err
+	}
+
 	args := []string{
 		execPath,
 
 		// These settings allow us to keep the server files away from the
 		// actual data.
-		"--server-settings", filepath.Join(cfg.ServerDataPath, "server-settings.json"),
-		"--server-banlist", filepath.Join(cfg.ServerDataPath, "server-banlist.json"),
-		"--server-whitelist", filepath.Join(cfg.ServerDataPath, "server-whitelist.json"),
-		"--server-adminlist", filepath.Join(cfg.ServerDataPath, "server-adminlist.json"),
+		"--server-settings", serverSettingsPath(cfg),
+		"--server-banlist", filepath.Join(cfg.ServerDataPath, players.BanListFile),
+		"--server-whitelist", filepath.Join(cfg.ServerDataPath, players.WhitelistFile),
+		"--server-adminlist", filepath.Join(cfg.ServerDataPath, players.AdminListFile),
 		"--server-id", filepath.Join(cfg.ServerDataPath, "server-id.json"),
-		"--use-server-whitelist",
 
 		"--start-server-load-latest",
+		"--port", strconv.Itoa(cfg.Port),
 	}
 
-	if cfg.Factocord.Enabled {
-		return runFactocord(ctx, cfg, args)
+	if cfg.BindAddress != "" {
+		args = append(args, "--bind", cfg.BindAddress)
 	}
 
-	return runVanilla(ctx, cfg, args)
+	lists, err := players.ReadFiles(cfg.ServerDataPath)
+	if err != nil {
+		return fmt.Errorf("failed to read player lists: %w", err)
+	}
+	if useWhitelist(cfg, lists) {
+		args = append(args, "--use-server-whitelist")
+	}
+
+	srv, rconArgs, err := newServer(cfg)
+	if err != nil {
+		return err
+	}
+	args = append(args, rconArgs...)
+
+	if cfg.Logs.Enabled {
+		args = append(args, "--console-log", filepath.Join(serverlog.Dir(cfg.ServerDataPath), serverlog.ConsoleFile))
+	}
+
+	// Extra arguments come last so that they can't be mistaken for the
+	// value of one of our flags.
+	args = append(args, cfg.ExtraArgs...)
+
+	err = runVanilla(ctx, log, cfg, srv, notifyHooks(n, cfg, hooks), out, args)
+	notifyExit(n, cfg, err)
+	return err
 }
@@ -0,0 +1,100 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher_test
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"gopkg.in/ini.v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetupConfigAppliesOverrides(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		InstallPath:    t.TempDir(),
+		ServerDataPath: "/data",
+		INI: map[string]map[string]string{
+			"other": {"port": "34198", "non-blocking-saving": "true"},
+			"new":   {"key": "value"},
+		},
+	}
+	configPath := filepath.Join(cfg.InstallPath, "config", "config.ini")
+
+	assert.NilError(t, launcher.SetupConfig(log, cfg))
+	conf, err := ini.Load(configPath)
+	assert.NilError(t, err)
+	assert.Equal(t, conf.Section("path").Key("write-data").String(), "/data")
+	assert.Equal(t, conf.Section("other").Key("port").String(), "34198")
+	assert.Equal(t, conf.Section("other").Key("non-blocking-saving").String(), "true")
+	assert.Equal(t, conf.Section("new").Key("key").String(), "value")
+
+	// Changes made by hand are kept, unless reset is enabled.
+	conf.Section("general").Key("locale").SetValue("de")
+	assert.NilError(t, conf.SaveTo(configPath))
+	cfg.INI = nil
+	assert.NilError(t, launcher.SetupConfig(log, cfg))
+	conf, err = ini.Load(configPath)
+	assert.NilError(t, err)
+	assert.Equal(t, conf.Section("general").Key("locale").String(), "de")
+	assert.Equal(t, conf.Section("other").Key("port").String(), "34198")
+
+	cfg.INIReset = true
+	assert.NilError(t, launcher.SetupConfig(log, cfg))
+	b, err := os.ReadFile(configPath)
+	assert.NilError(t, err)
+	conf, err = ini.Load(b)
+	assert.NilError(t, err)
+	assert.Equal(t, conf.Section("general").Key("locale").String(), "")
+	assert.Assert(t, !conf.Section("other").HasKey("port"))
+	assert.Equal(t, conf.Section("path").Key("write-data").String(), "/data")
+}
+
+func TestCheckPortsReportsConflicts(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer udp.Close()
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer tcp.Close()
+
+	cfg := &config.Config{
+		BindAddress:  "127.0.0.1",
+		Port:         udp.LocalAddr().(*net.UDPAddr).Port,
+		RCONBind:     "127.0.0.1",
+		RCONPort:     tcp.Addr().(*net.TCPAddr).Port,
+		RCONPassword: "secret",
+	}
+
+	err = launcher.CheckPorts(cfg)
+	assert.ErrorContains(t, err, "game port 127.0.0.1:")
+	assert.ErrorContains(t, err, "/udp is already in use, set FACTORIO_PORT")
+	assert.ErrorContains(t, err, "/tcp is already in use, set FACTORIO_RCON_PORT")
+
+	udp.Close()
+	tcp.Close()
+	assert.NilError(t, launcher.CheckPorts(cfg))
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+)
+
+// notifyHooks returns hooks that send events to n before calling the
+// provided hooks.
+func notifyHooks(n *notify.Notifier, cfg *config.Config, hooks Hooks) Hooks {
+	return Hooks{
+		OnReady: func(srv *Server) {
+			n.Notify(notify.Event{
+				Type:    notify.EventServerStarted,
+				Message: fmt.Sprintf("Factorio %s server started", cfg.Version),
+				Version: cfg.Version,
+			})
+			if hooks.OnReady != nil {
+				hooks.OnReady(srv)
+			}
+		},
+		OnConsoleEvent: func(ev factorio.ConsoleEvent) {
+			switch ev.Type {
+			case factorio.ConsoleEventJoin:
+				n.Notify(notify.Event{
+					Type:    notify.EventPlayerJoined,
+					Message: ev.Player + " joined the game",
+					Player:  ev.Player,
+				})
+			case factorio.ConsoleEventLeave:
+				n.Notify(notify.Event{
+					Type:    notify.EventPlayerLeft,
+					Message: ev.Player + " left the game",
+					Player:  ev.Player,
+				})
+			}
+			if hooks.OnConsoleEvent != nil {
+				hooks.OnConsoleEvent(ev)
+			}
+		},
+	}
+}
+
+// notifyExit sends an event to n about the server exiting with err. If
+// the server itself failed, only its exit status is sent.
+func notifyExit(n *notify.Notifier, cfg *config.Config, err error) {
+	if err != nil {
+		reason := err.Error()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			reason = exitErr.String()
+		}
+
+		n.Notify(notify.Event{
+			Type:    notify.EventServerCrashed,
+			Message: fmt.Sprintf("Factorio %s server crashed", cfg.Version),
+			Version: cfg.Version,
+			Error:   reason,
+		})
+		return
+	}
+
+	n.Notify(notify.Event{
+		Type:    notify.EventServerStopped,
+		Message: fmt.Sprintf("Factorio %s server stopped", cfg.Version),
+		Version: cfg.Version,
+	})
+}
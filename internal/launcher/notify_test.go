@@ -0,0 +1,88 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+	"gotest.tools/v3/assert"
+)
+
+func TestCrashEventDoesNotLeakArguments(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	hs := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body) //nolint:errcheck // Why: Test.
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+	}))
+	defer hs.Close()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	n, err := notify.New(log, config.Webhooks{URLs: []string{hs.URL}, ContentType: "application/json", Timeout: 5 * time.Second})
+	assert.NilError(t, err)
+	go n.Run(context.Background())
+
+	cfg := &config.Config{InstallPath: t.TempDir(), Version: "2.0.28"}
+	err = launcher.RunVanilla(context.Background(), log, cfg, nil, launcher.Hooks{}, nil,
+		[]string{"/bin/sh", "-c", "exit 3", "--rcon-password", "hunter2"})
+	assert.ErrorContains(t, err, "exit status 3")
+	assert.Assert(t, !strings.Contains(err.Error(), "hunter2"), err.Error())
+
+	launcher.NotifyExit(n, cfg, err)
+	n.Close(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, len(bodies), 1)
+	assert.Assert(t, strings.Contains(bodies[0], `"error":"exit status 3"`), bodies[0])
+	assert.Assert(t, !strings.Contains(bodies[0], "hunter2"), bodies[0])
+}
+
+func TestReadyHookIsCalledOnce(t *testing.T) {
+	const (
+		chat  = "2024-01-02 03:04:05 [CHAT] mallory: changing state from(CreatingGame) to(InGame)"
+		ready = "   2.345 Info ServerMultiplayerManager.cpp:923: changing state from(CreatingGame) to(InGame)"
+	)
+
+	var calls int
+	hooks := launcher.Hooks{OnReady: func(*launcher.Server) { calls++ }}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{InstallPath: t.TempDir()}
+
+	// Chat can't fake the server becoming ready.
+	assert.NilError(t, launcher.RunVanilla(context.Background(), log, cfg, nil, hooks, nil,
+		[]string{"/bin/sh", "-c", "echo '" + chat + "'"}))
+	assert.Equal(t, calls, 0)
+
+	assert.NilError(t, launcher.RunVanilla(context.Background(), log, cfg, nil, hooks, nil,
+		[]string{"/bin/sh", "-c", "echo '" + ready + "'; echo '" + chat + "'; echo '" + ready + "'"}))
+	assert.Equal(t, calls, 1)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/serverlog"
+)
+
+// lineWriter is an io.Writer that calls fn for every complete line
+// written to it.
+type lineWriter struct {
+	mu  sync.Mutex
+	buf []byte
+	fn  func(line string)
+}
+
+// newLineWriter returns a lineWriter that calls fn for every line.
+func newLineWriter(fn func(line string)) *lineWriter {
+	return &lineWriter{fn: fn}
+}
+
+// Write implements io.Writer.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.fn(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush calls fn with any remaining output that didn't end with a
+// newline.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.fn(string(bytes.TrimSuffix(w.buf, []byte("\r"))))
+		w.buf = nil
+	}
+}
+
+// gameOutput handles the output of a Factorio process. Every line is
+// logged, with a source=factorio attribute, and, if out is not nil, also
+// written to it.
+type gameOutput struct {
+	log *slog.Logger
+	out *serverlog.Writer
+
+	// logFailed is set once writing to out fails, so that only the first
+	// failure is reported.
+	logFailed atomic.Bool
+}
+
+// newGameOutput returns a gameOutput that logs to log and writes to out,
+// if it is not nil.
+func newGameOutput(log *slog.Logger, out *serverlog.Writer) *gameOutput {
+	return &gameOutput{log: log, out: out}
+}
+
+// line handles a line output by Factorio, logging it at level.
+func (g *gameOutput) line(ctx context.Context, line string, level slog.Level) {
+	if g.out != nil {
+		if err := g.out.WriteLine(line); err != nil && !g.logFailed.Swap(true) {
+			g.log.Warn("Failed to write server log", "err", err)
+		}
+	}
+	g.log.With("source", "factorio").Log(ctx, level, line)
+}
+
+// stdout returns a writer for the standard output of a Factorio process,
+// calling fn, if not nil, for every line after it has been handled.
+func (g *gameOutput) stdout(ctx context.Context, fn func(line string)) *lineWriter {
+	return newLineWriter(func(line string) {
+		g.line(ctx, line, factorio.LineLevel(line))
+		if fn != nil {
+			fn(line)
+		}
+	})
+}
+
+// stderr returns a writer for the standard error of a Factorio process.
+// Lines are logged as warnings, at least.
+func (g *gameOutput) stderr(ctx context.Context) *lineWriter {
+	return newLineWriter(func(line string) {
+		g.line(ctx, line, max(factorio.LineLevel(line), slog.LevelWarn))
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// checkPorts ensures that the ports the server listens on are free, so
+// that conflicts are reported clearly instead of by Factorio failing to
+// start. Every conflict found is returned.
+func checkPorts(cfg *config.Config) error {
+	var errs []error
+
+	game := net.JoinHostPort(cfg.BindAddress, strconv.Itoa(cfg.Port))
+	if conn, err := net.ListenPacket("udp", game); err != nil {
+		errs = append(errs, portError("game", game+"/udp", cfg.EnvName(&cfg.Port), err))
+	} else {
+		conn.Close() //nolint:errcheck // Why: Best effort.
+	}
+
+	rcon := rconBindAddress(cfg)
+	if l, err := net.Listen("tcp", rcon); err != nil {
+		errs = append(errs, portError("RCON", rcon+"/tcp", cfg.EnvName(&cfg.RCONPort), err))
+	} else {
+		l.Close() //nolint:errcheck // Why: Best effort.
+	}
+
+	return errors.Join(errs...)
+}
+
+// portError returns an error describing why listening on addr failed.
+func portError(name, addr, env string, err error) error {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return fmt.Errorf("%s port %s is already in use, set %s to use another port", name, addr, env)
+	}
+	return fmt.Errorf("failed to listen on %s port %s: %w", name, addr, err)
+}
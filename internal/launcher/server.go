@@ -0,0 +1,202 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"strconv"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"github.com/jaredallard/factorio-docker/internal/rcon"
+)
+
+// Server is a running Factorio server.
+type Server struct {
+	// rconAddr is the address to connect to RCON on.
+	rconAddr string
+
+	// rconPassword is the password for RCON.
+	rconPassword string
+}
+
+// rconBindAddress returns the address RCON should listen on. Unless
+// configured, RCON listens on every interface if a password is set, and
+// only on localhost otherwise.
+func rconBindAddress(cfg *config.Config) string {
+	host := cfg.RCONBind
+	if host == "" {
+		host = "0.0.0.0"
+		if cfg.RCONPassword == "" {
+			host = "127.0.0.1"
+		}
+	}
+	return net.JoinHostPort(host, strconv.Itoa(cfg.RCONPort))
+}
+
+// rconDialAddress returns the address to connect to RCON on, given the
+// address it listens on.
+func rconDialAddress(bind string) string {
+	host, port, _ := net.SplitHostPort(bind) //nolint:errcheck // Why: Created by rconBindAddress.
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+		if ip.To4() == nil {
+			host = "::1"
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// newServer returns a Server for the provided configuration and the
+// arguments needed to enable RCON on it. If no RCON password is
+// configured, a random one is generated so that the wrapper can always
+// manage the server.
+func newServer(cfg *config.Config) (*Server, []string, error) {
+	password := cfg.RCONPassword
+	if password == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate RCON password: %w", err)
+		}
+		password = hex.EncodeToString(b)
+	}
+
+	bind := rconBindAddress(cfg)
+	return &Server{rconAddr: rconDialAddress(bind), rconPassword: password},
+		[]string{"--rcon-bind", bind, "--rcon-password", password}, nil
+}
+
+// RCON runs a command on the server over RCON and returns its output.
+func (s *Server) RCON(ctx context.Context, cmd string) (string, error) {
+	c, err := rcon.Dial(ctx, s.rconAddr, s.rconPassword)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to RCON: %w", err)
+	}
+	defer c.Close() //nolint:errcheck // Why: Best effort.
+
+	return c.Execute(cmd)
+}
+
+// onlinePlayersRegexp matches the output of '/players online count',
+// e.g., "Online players (2):", capturing the number of players.
+var onlinePlayersRegexp = regexp.MustCompile(`Online players \((\d+)\)`)
+
+// OnlinePlayers returns the number of players online.
+func (s *Server) OnlinePlayers(ctx context.Context) (int, error) {
+	out, err := s.RCON(ctx, "/players online count")
+	if err != nil {
+		return 0, err
+	}
+
+	m := onlinePlayersRegexp.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("unexpected output from /players: %q", out)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// DesiredPlayers returns the player lists that should be applied to the
+// server, reading them from the configured sync source, if any.
+func DesiredPlayers(ctx context.Context, cfg *config.Config) (*players.Lists, error) {
+	desired := cfg.Players.Lists()
+	if cfg.Players.SyncSource == "" {
+		return desired, nil
+	}
+
+	// Never take longer than the sync interval, so that syncs don't pile
+	// up behind a source that hangs.
+	timeout := players.FetchTimeout
+	if i := cfg.Players.SyncInterval; i > 0 && i < timeout {
+		timeout = i
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	synced, err := players.Fetch(ctx, cfg.Players.SyncSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return players.Merge(desired, synced), nil
+}
+
+// ApplyPlayers applies the managed player lists in desired to the
+// running server, then writes them to the server data path. Every change
+// made is logged.
+func (s *Server) ApplyPlayers(ctx context.Context, log *slog.Logger, cfg *config.Config, desired *players.Lists) error {
+	current, err := players.ReadFiles(cfg.ServerDataPath)
+	if err != nil {
+		return fmt.Errorf("failed to read player lists: %w", err)
+	}
+
+	for _, c := range players.Diff(current, desired) {
+		log.Info("Updating player lists", "command", c.Command())
+		if _, err := s.RCON(ctx, c.Command()); err != nil {
+			return fmt.Errorf("failed to run %q: %w", c.Command(), err)
+		}
+	}
+
+	merged := players.Merge(current, desired)
+	if err := players.WriteFiles(cfg.ServerDataPath, merged); err != nil {
+		return err
+	}
+
+	// The whitelist may have been emptied or populated, so enforce it
+	// accordingly.
+	cmd := "/whitelist disable"
+	if useWhitelist(cfg, merged) {
+		cmd = "/whitelist enable"
+	}
+	if _, err := s.RCON(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to run %q: %w", cmd, err)
+	}
+
+	return nil
+}
+
+// useWhitelist returns true if the whitelist should be enforced, based
+// on the configured mode and the current player lists.
+func useWhitelist(cfg *config.Config, l *players.Lists) bool {
+	switch cfg.Players.WhitelistMode {
+	case "on":
+		return true
+	case "off":
+		return false
+	default: // auto
+		return len(l.Whitelist) > 0
+	}
+}
+
+// writePlayers writes the managed player lists to the server data path,
+// so that the server starts with them. If the sync source can't be read,
+// the lists in the configuration are used instead.
+func writePlayers(ctx context.Context, log *slog.Logger, cfg *config.Config) error {
+	desired, err := DesiredPlayers(ctx, cfg)
+	if err != nil {
+		log.Warn("Failed to sync player lists, using configured lists", "err", err)
+		desired = cfg.Players.Lists()
+	}
+
+	return players.WriteFiles(cfg.ServerDataPath, desired)
+}
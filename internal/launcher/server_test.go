@@ -0,0 +1,153 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"github.com/jaredallard/factorio-docker/internal/rcon/rcontest"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyPlayers(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, players.WriteFiles(dir, &players.Lists{
+		Admins:    []string{"alice"},
+		Whitelist: []string{"bob"},
+	}))
+
+	rs := rcontest.NewServer("secret", func(string) string { return "" })
+	defer rs.Close()
+
+	cfg := &config.Config{
+		ServerDataPath: dir,
+		Players: config.Players{
+			Whitelist: []string{"carol"},
+			Bans:      []players.Ban{{Username: "mallory", Reason: "griefing"}},
+		},
+	}
+
+	srv := launcher.NewTestServer(rs.Addr, "secret")
+	assert.NilError(t, srv.ApplyPlayers(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), cfg,
+		cfg.Players.Lists()))
+	assert.DeepEqual(t, rs.Commands(), []string{
+		"/whitelist add carol",
+		"/whitelist remove bob",
+		"/ban mallory griefing",
+		"/whitelist enable",
+	})
+
+	// Unmanaged lists are left as they were.
+	l, err := players.ReadFiles(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, l, &players.Lists{
+		Admins:    []string{"alice"},
+		Whitelist: []string{"carol"},
+		Bans:      []players.Ban{{Username: "mallory", Reason: "griefing"}},
+	})
+}
+
+func TestSyncedPlayersTakePrecedence(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"admins": ["carol"], "whitelist": []}`)) //nolint:errcheck // Why: Test.
+	}))
+	defer hs.Close()
+
+	dir := t.TempDir()
+	assert.NilError(t, players.WriteFiles(dir, &players.Lists{Admins: []string{"alice"}}))
+
+	rs := rcontest.NewServer("secret", func(string) string { return "" })
+	defer rs.Close()
+
+	cfg := &config.Config{
+		ServerDataPath: dir,
+		Players: config.Players{
+			Admins:     []string{"alice", "bob"},
+			Bans:       []players.Ban{{Username: "mallory"}},
+			SyncSource: hs.URL,
+		},
+	}
+
+	desired, err := launcher.DesiredPlayers(context.Background(), cfg)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, desired, &players.Lists{
+		Admins:    []string{"carol"},
+		Whitelist: []string{},
+		Bans:      []players.Ban{{Username: "mallory"}},
+	})
+
+	srv := launcher.NewTestServer(rs.Addr, "secret")
+	assert.NilError(t, srv.ApplyPlayers(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), cfg, desired))
+	assert.DeepEqual(t, rs.Commands(), []string{
+		"/promote carol",
+		"/demote alice",
+		"/ban mallory",
+		"/whitelist disable",
+	})
+}
+
+func TestDesiredPlayersTimesOut(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hs.Close()
+
+	cfg := &config.Config{Players: config.Players{SyncSource: hs.URL, SyncInterval: 50 * time.Millisecond}}
+	start := time.Now()
+	_, err := launcher.DesiredPlayers(context.Background(), cfg)
+	assert.ErrorContains(t, err, "failed to fetch player lists")
+	assert.Assert(t, time.Since(start) < 5*time.Second)
+}
+
+func TestUseWhitelist(t *testing.T) {
+	empty := &players.Lists{Whitelist: []string{}}
+	populated := &players.Lists{Whitelist: []string{"alice"}}
+
+	for mode, want := range map[string][2]bool{
+		"on":   {true, true},
+		"off":  {false, false},
+		"auto": {false, true},
+	} {
+		cfg := &config.Config{Players: config.Players{WhitelistMode: mode}}
+		assert.Equal(t, launcher.UseWhitelist(cfg, empty), want[0], mode)
+		assert.Equal(t, launcher.UseWhitelist(cfg, populated), want[1], mode)
+	}
+}
+
+func TestOnlinePlayers(t *testing.T) {
+	rs := rcontest.NewServer("secret", func(cmd string) string {
+		if cmd == "/players online count" {
+			return "Online players (2):\n"
+		}
+		return "Unknown command"
+	})
+	defer rs.Close()
+
+	n, err := launcher.NewTestServer(rs.Addr, "secret").OnlinePlayers(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+}
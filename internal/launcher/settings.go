@@ -0,0 +1,84 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// serverSettingsPath returns the path to the server-settings.json file.
+func serverSettingsPath(cfg *config.Config) string {
+	return filepath.Join(cfg.ServerDataPath, "server-settings.json")
+}
+
+// UpdateServerSettings updates server-settings.json with the values
+// provided in the configuration, such as credentials and the game
+// password. Values that are not set in the configuration are left
+// untouched. The server only reads this file on start.
+func UpdateServerSettings(cfg *config.Config) error {
+	overrides := map[string]string{
+		"username":      cfg.Username,
+		"token":         cfg.Token,
+		"game_password": cfg.GamePassword,
+	}
+
+	path := serverSettingsPath(cfg)
+	b, err := os.ReadFile(path) //nolint:gosec // Why: By design.
+	if err != nil {
+		return fmt.Errorf("failed to read server settings: %w", err)
+	}
+
+	var settings map[string]any
+	if err := json.Unmarshal(b, &settings); err != nil {
+		return fmt.Errorf("failed to unmarshal server settings: %w", err)
+	}
+
+	changed := false
+	for k, v := range overrides {
+		if v == "" || settings[k] == v {
+			continue
+		}
+
+		settings[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	b, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal server settings: %w", err)
+	}
+
+	// The settings may now contain secrets, so ensure only we can read
+	// them.
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write server settings: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("failed to restrict server settings permissions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// emptyCheckInterval is how often the server is checked for online
+// players while waiting for it to be empty.
+var emptyCheckInterval = time.Minute
+
+// saveAttempts is how many times saving the server is attempted before
+// giving up, waiting saveRetryDelay between attempts.
+var (
+	saveAttempts   = 3
+	saveRetryDelay = 10 * time.Second
+)
+
+// upgradeWarnings are the times before an upgrade at which players are
+// warned about it.
+var upgradeWarnings = []time.Duration{
+	30 * time.Minute, 10 * time.Minute, 5 * time.Minute,
+	time.Minute, 30 * time.Second, 10 * time.Second,
+}
+
+// WaitForUpgrade waits until the server can be stopped for an upgrade
+// to version, returning false if the context was canceled first. In
+// 'wait' mode, that's once no players are online. In 'countdown' mode,
+// players online are warned until the countdown has passed.
+func (s *Server) WaitForUpgrade(ctx context.Context, log *slog.Logger, cfg config.AutoUpgrade, version string) bool {
+	for {
+		n, err := s.OnlinePlayers(ctx)
+		if err != nil {
+			log.Warn("Failed to check for online players", "err", err)
+		}
+		if err == nil && n == 0 {
+			return true
+		}
+		if err == nil && cfg.Mode == "countdown" {
+			return s.countdown(ctx, log, cfg.Countdown, version)
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(emptyCheckInterval):
+		}
+	}
+}
+
+// countdown warns players that the server is restarting to upgrade to
+// version, returning once d has passed or false if the context was
+// canceled first.
+func (s *Server) countdown(ctx context.Context, log *slog.Logger, d time.Duration, version string) bool {
+	deadline := time.Now().Add(d)
+	for {
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining <= 0 {
+			break
+		}
+
+		// Text sent over RCON that isn't a command is shown as chat.
+		msg := fmt.Sprintf("The server is restarting in %s to upgrade to Factorio %s", remaining, version)
+		if _, err := s.RCON(ctx, msg); err != nil {
+			log.Warn("Failed to warn players about upgrade", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(nextWarning(remaining)):
+		}
+	}
+
+	if _, err := s.RCON(ctx, "The server is restarting to upgrade to Factorio "+version); err != nil {
+		log.Warn("Failed to warn players about upgrade", "err", err)
+	}
+	return true
+}
+
+// nextWarning returns how long to wait before warning players again,
+// given the time remaining before an upgrade.
+func nextWarning(remaining time.Duration) time.Duration {
+	for _, w := range upgradeWarnings {
+		if w < remaining {
+			return remaining - w
+		}
+	}
+	return remaining
+}
+
+// Save saves the server, retrying a few times if it fails so that a
+// brief RCON hiccup doesn't hold up a restart.
+func (s *Server) Save(ctx context.Context, log *slog.Logger) error {
+	var err error
+	for i := range saveAttempts {
+		if i > 0 {
+			log.Warn("Failed to save the server, retrying", "err", err, "in", saveRetryDelay)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(saveRetryDelay):
+			}
+		}
+
+		if _, err = s.RCON(ctx, "/server-save"); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to save the server: %w", err)
+}
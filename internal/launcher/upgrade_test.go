@@ -0,0 +1,133 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package launcher_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/launcher"
+	"github.com/jaredallard/factorio-docker/internal/rcon/rcontest"
+	"gotest.tools/v3/assert"
+)
+
+func TestNextWarning(t *testing.T) {
+	tests := []struct {
+		remaining time.Duration
+		want      time.Duration
+	}{
+		{time.Hour, 30 * time.Minute},
+		{30 * time.Minute, 20 * time.Minute},
+		{7 * time.Minute, 2 * time.Minute},
+		{5 * time.Minute, 4 * time.Minute},
+		{45 * time.Second, 15 * time.Second},
+		{10 * time.Second, 10 * time.Second},
+		{3 * time.Second, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, launcher.NextWarning(tt.remaining), tt.want, tt.remaining.String())
+	}
+}
+
+// playersServer returns an RCON server reporting the number of online
+// players returned by online, recording every command sent to it.
+func playersServer(t *testing.T, online func() int) *rcontest.Server {
+	rs := rcontest.NewServer("secret", func(cmd string) string {
+		if cmd == "/players online count" {
+			return "Online players (" + strconv.Itoa(online()) + "):\n"
+		}
+		return ""
+	})
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func TestWaitForUpgradeWaitsForEmptyServer(t *testing.T) {
+	launcher.SetUpgradeTimings(t, 10*time.Millisecond, 10*time.Millisecond)
+
+	var checks atomic.Int32
+	rs := playersServer(t, func() int {
+		if checks.Add(1) < 3 {
+			return 1
+		}
+		return 0
+	})
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := launcher.NewTestServer(rs.Addr, "secret")
+	assert.Assert(t, srv.WaitForUpgrade(context.Background(), log, config.AutoUpgrade{Mode: "wait"}, "2.0.1"))
+	assert.DeepEqual(t, rs.Commands(), []string{
+		"/players online count", "/players online count", "/players online count",
+	})
+}
+
+func TestWaitForUpgradeStopsWhenCanceled(t *testing.T) {
+	launcher.SetUpgradeTimings(t, 10*time.Millisecond, 10*time.Millisecond)
+
+	rs := playersServer(t, func() int { return 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := launcher.NewTestServer(rs.Addr, "secret")
+	assert.Assert(t, !srv.WaitForUpgrade(ctx, log, config.AutoUpgrade{Mode: "wait"}, "2.0.1"))
+}
+
+func TestWaitForUpgradeCountsDown(t *testing.T) {
+	rs := playersServer(t, func() int { return 1 })
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := launcher.NewTestServer(rs.Addr, "secret")
+	assert.Assert(t, srv.WaitForUpgrade(context.Background(), log,
+		config.AutoUpgrade{Mode: "countdown", Countdown: time.Second}, "2.0.1"))
+	assert.DeepEqual(t, rs.Commands(), []string{
+		"/players online count",
+		"The server is restarting in 1s to upgrade to Factorio 2.0.1",
+		"The server is restarting to upgrade to Factorio 2.0.1",
+	})
+}
+
+func TestSave(t *testing.T) {
+	rs := rcontest.NewServer("secret", nil)
+	defer rs.Close()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	assert.NilError(t, launcher.NewTestServer(rs.Addr, "secret").Save(context.Background(), log))
+	assert.DeepEqual(t, rs.Commands(), []string{"/server-save"})
+}
+
+func TestSaveGivesUpAfterRetries(t *testing.T) {
+	launcher.SetUpgradeTimings(t, 10*time.Millisecond, 10*time.Millisecond)
+
+	// Nothing listens on the address once the server is closed.
+	rs := rcontest.NewServer("secret", nil)
+	rs.Close()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	start := time.Now()
+	err := launcher.NewTestServer(rs.Addr, "secret").Save(context.Background(), log)
+	assert.ErrorContains(t, err, "failed to save the server")
+	assert.Assert(t, time.Since(start) >= 20*time.Millisecond, "expected two retries")
+}
@@ -20,20 +20,42 @@ package launcher
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
+	"sync"
 	"syscall"
 
 	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/serverlog"
 )
 
-// runVanilla runs the Factorio server as normal.
-func runVanilla(ctx context.Context, cfg *config.Config, args []string) error {
+// runVanilla runs the Factorio server as normal. Every line output by
+// the server is logged, with a source=factorio attribute, and, if out
+// is not nil, also written to it.
+func runVanilla(ctx context.Context, log *slog.Logger, cfg *config.Config, srv *Server, hooks Hooks,
+	out *serverlog.Writer, args []string) error {
+	// The server only becomes ready once, so the hook is never called
+	// again, even if the ready line is somehow output again.
+	var ready sync.Once
+
+	output := newGameOutput(log, out)
+	stdout := output.stdout(ctx, func(line string) {
+		if factorio.IsReadyLine(line) && hooks.OnReady != nil {
+			ready.Do(func() { hooks.OnReady(srv) })
+		}
+		if ev, ok := factorio.ParseConsoleEvent(line); ok && hooks.OnConsoleEvent != nil {
+			hooks.OnConsoleEvent(ev)
+		}
+	})
+	stderr := output.stderr(ctx)
+
 	//nolint:gosec // Why: We're creating the arguments above.
 	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 	cmd.Dir = cfg.InstallPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	cmd.Stdin = os.Stdin
 
 	// Start the process in a new process group so we can kill it and all
@@ -45,14 +67,19 @@ func runVanilla(ctx context.Context, cfg *config.Config, args []string) error {
 		return cmd.Process.Signal(syscall.SIGINT)
 	}
 
+	// Errors don't include the command line, since it contains the RCON
+	// password and is sent to webhooks and Discord.
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start '%s': %w", cmd.String(), err)
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
 	}
 
-	if err := cmd.Wait(); err != nil {
+	err := cmd.Wait()
+	stdout.Flush()
+	stderr.Flush()
+	if err != nil {
 		// Only report errors if the context wasn't canceled.
 		if ctx.Err() == nil {
-			return fmt.Errorf("failed to run '%s': %w", cmd.String(), err)
+			return fmt.Errorf("failed to run %s: %w", args[0], err)
 		}
 	}
 
@@ -0,0 +1,362 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package notify sends server events, such as players joining, to
+// webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+)
+
+// EventType is the type of an Event.
+type EventType string
+
+// eventTypes contains every event type, in the order they were
+// registered with newEventType.
+var eventTypes []EventType
+
+// newEventType registers an event type, so that templates are loaded for
+// it.
+func newEventType(name string) EventType {
+	t := EventType(name)
+	eventTypes = append(eventTypes, t)
+	return t
+}
+
+// Contains all event types.
+var (
+	EventServerStarted     = newEventType("server_started")
+	EventServerStopped     = newEventType("server_stopped")
+	EventServerCrashed     = newEventType("server_crashed")
+	EventVersionUpgraded   = newEventType("version_upgraded")
+	EventVersionDowngraded = newEventType("version_downgraded")
+	EventVersionRolledBack = newEventType("version_rolled_back")
+	EventPlayerJoined      = newEventType("player_joined")
+	EventPlayerLeft        = newEventType("player_left")
+	EventBackupSucceeded   = newEventType("backup_succeeded")
+	EventBackupFailed      = newEventType("backup_failed")
+)
+
+// SignatureHeader is the header containing the HMAC-SHA256 signature of
+// the request body, in the format "sha256=<hex>", when a secret is
+// configured.
+const SignatureHeader = "X-Signature-256"
+
+// queueSize is how many events are buffered before new ones are
+// dropped.
+const queueSize = 100
+
+// retryBackoff is how long to wait before the first retry. It doubles
+// with every retry.
+var retryBackoff = time.Second
+
+// Event is something that happened to the server.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Message is a human readable description of the event.
+	Message string `json:"message"`
+
+	// Player is the player that joined or left.
+	Player string `json:"player,omitempty"`
+
+	// Version is the version of Factorio that is running or was
+	// installed.
+	Version string `json:"version,omitempty"`
+
+	// PreviousVersion is the version of Factorio that was upgraded from.
+	PreviousVersion string `json:"previous_version,omitempty"`
+
+	// Path is the path to the backup.
+	Path string `json:"path,omitempty"`
+
+	// Error describes why the server crashed or a backup failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Notifier sends events to the configured webhooks. A nil Notifier
+// discards all events, so callers don't need to check if notifications
+// are enabled.
+type Notifier struct {
+	log       *slog.Logger
+	cfg       config.Webhooks
+	http      *http.Client
+	templates map[EventType]*template.Template
+
+	queue chan Event
+
+	// mu protects secret, which can be changed by SetSecret while events
+	// are being sent.
+	mu     sync.Mutex
+	secret string
+
+	// done is closed to stop Run, which closes stopped once it has.
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// New returns a Notifier for the provided configuration, or nil if no
+// webhooks are configured. Templates are read from cfg.TemplateDir, if
+// set. Events are only sent once Run is called.
+func New(log *slog.Logger, cfg config.Webhooks) (*Notifier, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, nil //nolint:nilnil // Why: A nil Notifier is valid.
+	}
+
+	templates, err := loadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Notifier{
+		log:       log,
+		cfg:       cfg,
+		http:      &http.Client{Timeout: cfg.Timeout},
+		templates: templates,
+		queue:     make(chan Event, queueSize),
+		secret:    cfg.Secret,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}, nil
+}
+
+// SetSecret changes the secret used to sign events, e.g., once it has
+// been rotated. Events that haven't been sent yet are signed with it.
+func (n *Notifier) SetSecret(secret string) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.secret = secret
+}
+
+// signingSecret returns the secret events are signed with, if any.
+func (n *Notifier) signingSecret() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.secret
+}
+
+// templateFuncs are the functions available to templates.
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g., for embedding strings in a
+	// JSON template.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// loadTemplates loads the template for each event type from dir, named
+// "<event type>.tmpl". Events without a template are sent as JSON.
+func loadTemplates(dir string) (map[EventType]*template.Template, error) {
+	templates := make(map[EventType]*template.Template)
+	if dir == "" {
+		return templates, nil
+	}
+
+	for _, t := range eventTypes {
+		path := filepath.Join(dir, string(t)+".tmpl")
+		b, err := os.ReadFile(path) //nolint:gosec // Why: By design.
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read webhook template: %w", err)
+		}
+
+		tmpl, err := template.New(string(t)).Funcs(templateFuncs).Parse(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse webhook template %s: %w", path, err)
+		}
+		templates[t] = tmpl
+	}
+
+	return templates, nil
+}
+
+// Notify queues an event to be sent to every webhook. It never blocks;
+// if too many events are queued, the event is dropped.
+func (n *Notifier) Notify(ev Event) {
+	if n == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	select {
+	case n.queue <- ev:
+	default:
+		n.log.Warn("Too many webhook events queued, dropping event", "type", ev.Type)
+	}
+}
+
+// Run sends queued events until Close is called. Events are sent with
+// the provided context, so it should outlive the server to ensure that
+// events about it stopping are sent.
+func (n *Notifier) Run(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	defer close(n.stopped)
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case ev := <-n.queue:
+			n.send(ctx, ev)
+		}
+	}
+}
+
+// Close stops Run and sends every queued event, giving up once the
+// context is canceled.
+func (n *Notifier) Close(ctx context.Context) {
+	if n == nil {
+		return
+	}
+
+	// Wait for Run to finish sending the current event, if any.
+	close(n.done)
+	select {
+	case <-n.stopped:
+	case <-ctx.Done():
+		return
+	}
+
+	for {
+		select {
+		case ev := <-n.queue:
+			n.send(ctx, ev)
+		default:
+			return
+		}
+	}
+}
+
+// send sends an event to every webhook.
+func (n *Notifier) send(ctx context.Context, ev Event) {
+	body, err := n.render(ev)
+	if err != nil {
+		n.log.Error("Failed to render webhook", "type", ev.Type, "err", err)
+		return
+	}
+
+	for _, url := range n.cfg.URLs {
+		if err := n.post(ctx, url, body); err != nil {
+			n.log.Warn("Failed to send webhook", "type", ev.Type, "err", err)
+		}
+	}
+}
+
+// render returns the body of the webhook for an event.
+func (n *Notifier) render(ev Event) ([]byte, error) {
+	tmpl, ok := n.templates[ev.Type]
+	if !ok {
+		return json.Marshal(ev)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ev); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends body to url, retrying failures with exponential backoff.
+func (n *Notifier) post(ctx context.Context, url string, body []byte) error {
+	backoff := retryBackoff
+	var err error
+	for attempt := 0; attempt <= n.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = n.postOnce(ctx, url, body)
+		if err == nil || !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// postOnce sends body to url once, returning if a failure should be
+// retried.
+func (n *Notifier) postOnce(ctx context.Context, url string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", n.cfg.ContentType)
+	if secret := n.signingSecret(); secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()                               //nolint:errcheck // Why: Best effort.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16)) //nolint:errcheck // Why: Allows connection reuse.
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Client errors won't be fixed by retrying, except for rate
+		// limits.
+		retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return false, nil
+}
+
+// Sign returns the signature of body using secret, in the format used
+// by SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/config"
+	"github.com/jaredallard/factorio-docker/internal/notify"
+	"gotest.tools/v3/assert"
+)
+
+// recorder is a webhook that fails the first failures requests.
+type recorder struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	b, _ := io.ReadAll(r.Body) //nolint:errcheck // Why: Test.
+	rec.requests = append(rec.requests, r)
+	rec.bodies = append(rec.bodies, b)
+
+	if rec.failures > 0 {
+		rec.failures--
+		w.WriteHeader(http.StatusBadGateway)
+	}
+}
+
+func newNotifier(t *testing.T, cfg config.Webhooks) *notify.Notifier {
+	t.Cleanup(notify.SetRetryBackoff(time.Millisecond))
+
+	cfg.ContentType = "application/json"
+	cfg.Timeout = 5 * time.Second
+	n, err := notify.New(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	assert.NilError(t, err)
+	return n
+}
+
+func TestNotifierRetriesAndSigns(t *testing.T) {
+	rec := &recorder{failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newNotifier(t, config.Webhooks{URLs: []string{srv.URL}, Secret: "secret", Retries: 2})
+	go n.Run(context.Background())
+	n.Notify(notify.Event{Type: notify.EventPlayerJoined, Message: "alice joined the game", Player: "alice"})
+	n.Close(context.Background())
+
+	assert.Equal(t, len(rec.bodies), 3)
+	body := rec.bodies[2]
+
+	var ev notify.Event
+	assert.NilError(t, json.Unmarshal(body, &ev))
+	assert.Equal(t, ev.Type, notify.EventPlayerJoined)
+	assert.Equal(t, ev.Player, "alice")
+	assert.Assert(t, !ev.Time.IsZero())
+
+	assert.Equal(t, rec.requests[2].Header.Get(notify.SignatureHeader), notify.Sign("secret", body))
+	assert.Equal(t, rec.requests[2].Header.Get("Content-Type"), "application/json")
+}
+
+func TestNotifierUsesRotatedSecret(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newNotifier(t, config.Webhooks{URLs: []string{srv.URL}, Secret: "old"})
+	n.SetSecret("new")
+	go n.Run(context.Background())
+	n.Notify(notify.Event{Type: notify.EventServerStarted, Message: "Server started"})
+	n.Close(context.Background())
+
+	assert.Equal(t, len(rec.bodies), 1)
+	assert.Equal(t, rec.requests[0].Header.Get(notify.SignatureHeader), notify.Sign("new", rec.bodies[0]))
+}
+
+func TestNotifierGivesUpOnClientErrors(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := newNotifier(t, config.Webhooks{URLs: []string{srv.URL}, Retries: 3})
+	go n.Run(context.Background())
+	n.Notify(notify.Event{Type: notify.EventServerStopped})
+	n.Close(context.Background())
+
+	assert.Equal(t, requests, 1)
+}
+
+func TestNotifierUsesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "server_crashed.tmpl"),
+		[]byte(`{"text": {{ json .Message }}, "error": {{ json .Error }}}`), 0o600))
+
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newNotifier(t, config.Webhooks{URLs: []string{srv.URL}, TemplateDir: dir})
+	go n.Run(context.Background())
+	n.Notify(notify.Event{Type: notify.EventServerCrashed, Message: `server "crashed"`, Error: "exit status 1"})
+	n.Close(context.Background())
+
+	assert.Equal(t, len(rec.bodies), 1)
+	assert.Equal(t, string(rec.bodies[0]), `{"text": "server \"crashed\"", "error": "exit status 1"}`)
+}
+
+func TestEveryEventTypeHasTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for _, typ := range notify.EventTypes() {
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, string(typ)+".tmpl"), []byte(string(typ)), 0o600))
+	}
+
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newNotifier(t, config.Webhooks{URLs: []string{srv.URL}, TemplateDir: dir})
+	go n.Run(context.Background())
+	n.Notify(notify.Event{Type: notify.EventVersionRolledBack})
+	n.Close(context.Background())
+
+	assert.Equal(t, len(rec.bodies), 1)
+	assert.Equal(t, string(rec.bodies[0]), "version_rolled_back")
+}
+
+func TestNilNotifierDiscardsEvents(t *testing.T) {
+	n, err := notify.New(slog.Default(), config.Webhooks{})
+	assert.NilError(t, err)
+	assert.Assert(t, n == nil)
+
+	n.Notify(notify.Event{Type: notify.EventServerStarted})
+	n.Close(context.Background())
+}
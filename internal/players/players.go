@@ -0,0 +1,284 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package players manages the lists of admins, whitelisted and banned
+// players of a Factorio server, both on disk and on a running server.
+package players
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// File names of each list, relative to the server data path.
+const (
+	AdminListFile = "server-adminlist.json"
+	WhitelistFile = "server-whitelist.json"
+	BanListFile   = "server-banlist.json"
+)
+
+// Ban is a banned player.
+type Ban struct {
+	// Username is the name of the banned player.
+	Username string `json:"username"`
+
+	// Reason is why the player was banned, if provided.
+	Reason string `json:"reason,omitempty"`
+}
+
+// UnmarshalText parses a ban in the format "username" or
+// "username:reason".
+func (b *Ban) UnmarshalText(text []byte) error {
+	username, reason, _ := strings.Cut(string(text), ":")
+	b.Username = strings.TrimSpace(username)
+	b.Reason = strings.TrimSpace(reason)
+	if b.Username == "" {
+		return fmt.Errorf("invalid ban %q: username is empty", string(text))
+	}
+	return nil
+}
+
+// UnmarshalJSON parses a ban as stored by Factorio, which is either an
+// object or, in older versions, just the username.
+func (b *Ban) UnmarshalJSON(data []byte) error {
+	var username string
+	if err := json.Unmarshal(data, &username); err == nil {
+		*b = Ban{Username: username}
+		return nil
+	}
+
+	type ban Ban
+	return json.Unmarshal(data, (*ban)(b))
+}
+
+// MarshalText returns the ban in the format accepted by UnmarshalText.
+func (b Ban) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// MarshalJSON returns the ban in the format stored by Factorio.
+func (b Ban) MarshalJSON() ([]byte, error) {
+	type ban Ban
+	return json.Marshal(ban(b))
+}
+
+// String returns the ban in the format accepted by UnmarshalText.
+func (b Ban) String() string {
+	if b.Reason == "" {
+		return b.Username
+	}
+	return b.Username + ":" + b.Reason
+}
+
+// Lists are the player lists of a server. A nil list is not managed,
+// while an empty list is managed and contains no players.
+type Lists struct {
+	Admins    []string
+	Whitelist []string
+	Bans      []Ban
+}
+
+// ReadFiles reads the player lists stored in the server data path.
+// Lists that don't exist are returned as empty.
+func ReadFiles(dataPath string) (*Lists, error) {
+	l := &Lists{Admins: []string{}, Whitelist: []string{}, Bans: []Ban{}}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{AdminListFile, &l.Admins},
+		{WhitelistFile, &l.Whitelist},
+		{BanListFile, &l.Bans},
+	}
+
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(dataPath, f.name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		if err := json.Unmarshal(b, f.v); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.name, err)
+		}
+	}
+
+	return l, nil
+}
+
+// WriteFiles writes every managed (non-nil) list to the server data
+// path. Lists that are not managed are left untouched.
+func WriteFiles(dataPath string, l *Lists) error {
+	files := []struct {
+		name    string
+		managed bool
+		v       any
+	}{
+		{AdminListFile, l.Admins != nil, l.Admins},
+		{WhitelistFile, l.Whitelist != nil, l.Whitelist},
+		{BanListFile, l.Bans != nil, l.Bans},
+	}
+
+	for _, f := range files {
+		if !f.managed {
+			continue
+		}
+
+		b, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dataPath, f.name), b, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Action is a change made to a player list.
+type Action string
+
+// Contains all supported actions.
+const (
+	ActionPromote         Action = "promote"
+	ActionDemote          Action = "demote"
+	ActionWhitelistAdd    Action = "whitelist add"
+	ActionWhitelistRemove Action = "whitelist remove"
+	ActionBan             Action = "ban"
+	ActionUnban           Action = "unban"
+)
+
+// Change is a single change needed to make one player list match
+// another.
+type Change struct {
+	Action   Action
+	Username string
+
+	// Reason is the reason for a ban.
+	Reason string
+}
+
+// Command returns the server command that applies the change.
+func (c Change) Command() string {
+	cmd := "/" + string(c.Action) + " " + c.Username
+	if c.Action == ActionBan && c.Reason != "" {
+		cmd += " " + c.Reason
+	}
+	return cmd
+}
+
+// String returns a human readable description of the change.
+func (c Change) String() string {
+	return c.Command()
+}
+
+// Diff returns the changes required to make current match desired. Lists
+// that are not managed in desired (nil) are ignored. Usernames are
+// compared case-insensitively, like Factorio does.
+func Diff(current, desired *Lists) []Change {
+	var changes []Change
+
+	if desired.Admins != nil {
+		added, removed := diffNames(current.Admins, desired.Admins)
+		changes = append(changes, toChanges(ActionPromote, added)...)
+		changes = append(changes, toChanges(ActionDemote, removed)...)
+	}
+
+	if desired.Whitelist != nil {
+		added, removed := diffNames(current.Whitelist, desired.Whitelist)
+		changes = append(changes, toChanges(ActionWhitelistAdd, added)...)
+		changes = append(changes, toChanges(ActionWhitelistRemove, removed)...)
+	}
+
+	if desired.Bans != nil {
+		currentBans := make(map[string]Ban, len(current.Bans))
+		for _, b := range current.Bans {
+			currentBans[strings.ToLower(b.Username)] = b
+		}
+		desiredBans := make(map[string]Ban, len(desired.Bans))
+		for _, b := range desired.Bans {
+			desiredBans[strings.ToLower(b.Username)] = b
+		}
+
+		for _, b := range desired.Bans {
+			if _, ok := currentBans[strings.ToLower(b.Username)]; !ok {
+				changes = append(changes, Change{Action: ActionBan, Username: b.Username, Reason: b.Reason})
+			}
+		}
+		for _, b := range current.Bans {
+			if _, ok := desiredBans[strings.ToLower(b.Username)]; !ok {
+				changes = append(changes, Change{Action: ActionUnban, Username: b.Username})
+			}
+		}
+	}
+
+	return changes
+}
+
+// diffNames returns the names in desired but not in current, and the
+// names in current but not in desired.
+func diffNames(current, desired []string) (added, removed []string) {
+	contains := func(names []string, name string) bool {
+		return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+	}
+
+	for _, name := range desired {
+		if !contains(current, name) {
+			added = append(added, name)
+		}
+	}
+	for _, name := range current {
+		if !contains(desired, name) {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+// toChanges returns a change with the provided action for every name.
+func toChanges(action Action, names []string) []Change {
+	changes := make([]Change, 0, len(names))
+	for _, name := range names {
+		changes = append(changes, Change{Action: action, Username: name})
+	}
+	return changes
+}
+
+// Merge returns current with every managed list replaced by the one in
+// desired.
+func Merge(current, desired *Lists) *Lists {
+	merged := *current
+	if desired.Admins != nil {
+		merged.Admins = desired.Admins
+	}
+	if desired.Whitelist != nil {
+		merged.Whitelist = desired.Whitelist
+	}
+	if desired.Bans != nil {
+		merged.Bans = desired.Bans
+	}
+	return &merged
+}
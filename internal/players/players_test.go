@@ -0,0 +1,85 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package players_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffOnlyChangesManagedLists(t *testing.T) {
+	current := &players.Lists{
+		Admins:    []string{"alice", "bob"},
+		Whitelist: []string{"carol"},
+		Bans:      []players.Ban{{Username: "mallory"}},
+	}
+	desired := &players.Lists{
+		Admins: []string{"Alice", "dave"},
+		Bans:   []players.Ban{{Username: "eve", Reason: "griefing"}},
+	}
+
+	var commands []string
+	for _, c := range players.Diff(current, desired) {
+		commands = append(commands, c.Command())
+	}
+	assert.DeepEqual(t, commands, []string{
+		"/promote dave",
+		"/demote bob",
+		"/ban eve griefing",
+		"/unban mallory",
+	})
+}
+
+func TestFilesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	// Older versions of Factorio store bans as plain usernames.
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, players.BanListFile),
+		[]byte(`["mallory", {"username": "eve", "reason": "griefing"}]`), 0o600))
+
+	l, err := players.ReadFiles(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, l, &players.Lists{
+		Admins:    []string{},
+		Whitelist: []string{},
+		Bans:      []players.Ban{{Username: "mallory"}, {Username: "eve", Reason: "griefing"}},
+	})
+
+	// Only managed lists are written.
+	assert.NilError(t, players.WriteFiles(dir, &players.Lists{Admins: []string{"alice"}}))
+	_, err = os.Stat(filepath.Join(dir, players.WhitelistFile))
+	assert.Assert(t, os.IsNotExist(err))
+
+	l, err = players.ReadFiles(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, l.Admins, []string{"alice"})
+	assert.Equal(t, len(l.Bans), 2)
+}
+
+func TestBanText(t *testing.T) {
+	var b players.Ban
+	assert.NilError(t, b.UnmarshalText([]byte("eve: griefing")))
+	assert.DeepEqual(t, b, players.Ban{Username: "eve", Reason: "griefing"})
+	assert.Equal(t, b.String(), "eve:griefing")
+
+	assert.ErrorContains(t, b.UnmarshalText([]byte(":reason")), "username is empty")
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package players
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxSourceSize is the largest player list source that is read.
+const maxSourceSize = 10 << 20
+
+// FetchTimeout is the longest fetching player lists from a URL may take.
+const FetchTimeout = 30 * time.Second
+
+// client is the HTTP client used to fetch player lists.
+var client = &http.Client{Timeout: FetchTimeout}
+
+// source is the format of an external player list source. Lists that are
+// not present are not managed by it.
+type source struct {
+	Admins    []string `json:"admins"`
+	Whitelist []string `json:"whitelist"`
+	Bans      []Ban    `json:"bans"`
+}
+
+// Fetch reads player lists from src, which is either an HTTP(S) URL or
+// the path to a local file. The source is a JSON object with the
+// optional keys "admins", "whitelist" and "bans", each being a list in
+// the same format Factorio stores it in. Lists that are not present are
+// returned as nil.
+func Fetch(ctx context.Context, src string) (*Lists, error) {
+	var b []byte
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, http.NoBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch player lists: %w", err)
+		}
+		defer resp.Body.Close() //nolint:errcheck // Why: Best effort.
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch player lists: unexpected status code %d", resp.StatusCode)
+		}
+
+		b, err = io.ReadAll(io.LimitReader(resp.Body, maxSourceSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read player lists: %w", err)
+		}
+	} else {
+		var err error
+		b, err = os.ReadFile(src) //nolint:gosec // Why: By design.
+		if err != nil {
+			return nil, fmt.Errorf("failed to read player lists: %w", err)
+		}
+	}
+
+	var s source
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse player lists from %s: %w", src, err)
+	}
+
+	return &Lists{Admins: s.Admins, Whitelist: s.Whitelist, Bans: s.Bans}, nil
+}
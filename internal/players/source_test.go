@@ -0,0 +1,61 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package players_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/players"
+	"gotest.tools/v3/assert"
+)
+
+func TestFetch(t *testing.T) {
+	const body = `{"whitelist": ["alice", "bob"], "bans": [{"username": "eve", "reason": "griefing"}]}`
+	want := &players.Lists{
+		Whitelist: []string{"alice", "bob"},
+		Bans:      []players.Ban{{Username: "eve", Reason: "griefing"}},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(body)) //nolint:errcheck // Why: Test.
+	}))
+	defer srv.Close()
+
+	l, err := players.Fetch(context.Background(), srv.URL)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, l, want)
+
+	path := filepath.Join(t.TempDir(), "players.json")
+	assert.NilError(t, os.WriteFile(path, []byte(body), 0o600))
+	l, err = players.Fetch(context.Background(), path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, l, want)
+}
+
+func TestFetchFailsOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := players.Fetch(context.Background(), srv.URL)
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package rcon implements a client for the Source RCON protocol, which
+// is used by Factorio to allow running commands remotely. For more
+// information, see:
+// https://developer.valvesoftware.com/wiki/Source_RCON_Protocol
+package rcon
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Packet types used by the protocol.
+const (
+	typeResponseValue int32 = 0
+	typeExecCommand   int32 = 2
+	typeAuthResponse  int32 = 2
+	typeAuth          int32 = 3
+)
+
+// maxPacketSize is the largest packet we accept from the server.
+const maxPacketSize = 1 << 20
+
+// timeout is how long to wait for the server to respond to a request.
+const timeout = 30 * time.Second
+
+// ErrAuthFailed is returned when the server rejects the password.
+var ErrAuthFailed = errors.New("rcon: authentication failed")
+
+// Client is a connection to an RCON server. It is safe for concurrent
+// use.
+type Client struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	nextID int32
+}
+
+// Dial connects to the RCON server at addr and authenticates with the
+// provided password.
+func Dial(ctx context.Context, addr, password string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, r: bufio.NewReader(conn), nextID: 1}
+	if err := c.auth(password); err != nil {
+		conn.Close() //nolint:errcheck // Why: Best effort.
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// auth authenticates the connection.
+func (c *Client) auth(password string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.id()
+	if err := c.write(id, typeAuth, password); err != nil {
+		return err
+	}
+
+	// Some servers send an empty response value before the auth
+	// response, so skip until we get the auth response.
+	for {
+		respID, typ, _, err := c.read()
+		if err != nil {
+			return err
+		}
+		if typ != typeAuthResponse {
+			continue
+		}
+
+		// A failed authentication is signaled with an ID of -1.
+		if respID == -1 {
+			return ErrAuthFailed
+		}
+		if respID != id {
+			return fmt.Errorf("rcon: unexpected auth response ID %d", respID)
+		}
+		return nil
+	}
+}
+
+// Execute runs a command on the server and returns its output.
+func (c *Client) Execute(cmd string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.id()
+	if err := c.write(id, typeExecCommand, cmd); err != nil {
+		return "", err
+	}
+
+	for {
+		respID, typ, body, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		if respID == id && typ == typeResponseValue {
+			return body, nil
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// id returns the ID to use for the next request. Must be called with
+// the lock held.
+func (c *Client) id() int32 {
+	id := c.nextID
+	c.nextID++
+	if c.nextID <= 0 {
+		c.nextID = 1
+	}
+	return id
+}
+
+// write sends a packet to the server.
+func (c *Client) write(id, typ int32, body string) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	// Size does not include itself, but includes the ID, type and the
+	// two null terminators.
+	//nolint:gosec // Why: Commands are nowhere near 2GiB.
+	size := int32(4 + 4 + len(body) + 2)
+	buf := make([]byte, 0, 4+size)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(size)) //nolint:gosec // Why: Always positive.
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(id))   //nolint:gosec // Why: Bit pattern is what matters.
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(typ))  //nolint:gosec // Why: Always positive.
+	buf = append(buf, body...)
+	buf = append(buf, 0, 0)
+
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// read reads a packet from the server.
+func (c *Client) read() (id, typ int32, body string, err error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, 0, "", err
+	}
+
+	var size int32
+	if err := binary.Read(c.r, binary.LittleEndian, &size); err != nil {
+		return 0, 0, "", err
+	}
+	if size < 10 || size > maxPacketSize {
+		return 0, 0, "", fmt.Errorf("rcon: invalid packet size %d", size)
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(c.r, buf); err != nil {
+		return 0, 0, "", err
+	}
+
+	id = int32(binary.LittleEndian.Uint32(buf[0:4]))  //nolint:gosec // Why: Bit pattern is what matters.
+	typ = int32(binary.LittleEndian.Uint32(buf[4:8])) //nolint:gosec // Why: Bit pattern is what matters.
+	return id, typ, string(buf[8 : len(buf)-2]), nil
+}
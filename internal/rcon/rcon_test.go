@@ -0,0 +1,56 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package rcon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaredallard/factorio-docker/internal/rcon"
+	"github.com/jaredallard/factorio-docker/internal/rcon/rcontest"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanExecuteCommands(t *testing.T) {
+	srv := rcontest.NewServer("hunter2", func(cmd string) string {
+		return "ran " + cmd
+	})
+	defer srv.Close()
+
+	c, err := rcon.Dial(context.Background(), srv.Addr, "hunter2")
+	assert.NilError(t, err)
+	defer c.Close()
+
+	out, err := c.Execute("/players online")
+	assert.NilError(t, err)
+	assert.Equal(t, out, "ran /players online")
+
+	out, err = c.Execute("/time")
+	assert.NilError(t, err)
+	assert.Equal(t, out, "ran /time")
+
+	assert.DeepEqual(t, srv.Commands(), []string{"/players online", "/time"})
+}
+
+func TestRejectsWrongPassword(t *testing.T) {
+	srv := rcontest.NewServer("hunter2", nil)
+	defer srv.Close()
+
+	_, err := rcon.Dial(context.Background(), srv.Addr, "hunter3")
+	assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+}
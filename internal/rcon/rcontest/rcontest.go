@@ -0,0 +1,144 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package rcontest provides a fake RCON server for use in tests, similar
+// to net/http/httptest.
+package rcontest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+)
+
+// Handler returns the response to a command sent to the server.
+type Handler func(cmd string) string
+
+// Server is a fake RCON server listening on a random local port.
+type Server struct {
+	// Addr is the address the server is listening on.
+	Addr string
+
+	l        net.Listener
+	password string
+	handler  Handler
+
+	mu       sync.Mutex
+	commands []string
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a fake RCON server that accepts the provided password
+// and responds to commands with handler. The server must be closed once
+// done.
+func NewServer(password string, handler Handler) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("rcontest: failed to listen: " + err.Error())
+	}
+
+	s := &Server{Addr: l.Addr().String(), l: l, password: password, handler: handler}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Commands returns every command received by the server, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.l.Close() //nolint:errcheck // Why: Best effort.
+	s.wg.Wait()
+}
+
+// serve accepts connections until the listener is closed.
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close() //nolint:errcheck // Why: Best effort.
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle serves a single connection.
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	authed := false
+	for {
+		var size int32
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			return
+		}
+
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return
+		}
+		id := int32(binary.LittleEndian.Uint32(buf[0:4]))  //nolint:gosec // Why: Test helper.
+		typ := int32(binary.LittleEndian.Uint32(buf[4:8])) //nolint:gosec // Why: Test helper.
+		body := string(buf[8 : len(buf)-2])
+
+		switch {
+		case typ == 3: // Auth
+			if body != s.password {
+				write(conn, -1, 2, "")
+				return
+			}
+			authed = true
+			write(conn, id, 2, "")
+		case typ == 2 && authed: // Exec command
+			s.mu.Lock()
+			s.commands = append(s.commands, body)
+			s.mu.Unlock()
+
+			resp := ""
+			if s.handler != nil {
+				resp = s.handler(body)
+			}
+			write(conn, id, 0, resp)
+		default:
+			return
+		}
+	}
+}
+
+// write writes a packet to conn.
+func write(conn net.Conn, id, typ int32, body string) {
+	size := int32(4 + 4 + len(body) + 2) //nolint:gosec // Why: Test helper.
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(size))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(id)) //nolint:gosec // Why: Test helper.
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(typ))
+	buf = append(buf, body...)
+	buf = append(buf, 0, 0)
+	conn.Write(buf) //nolint:errcheck // Why: Test helper.
+}
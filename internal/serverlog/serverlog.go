@@ -0,0 +1,233 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package serverlog stores the output of the Factorio server in the
+// server data path, rotating and compressing old logs, and reads it
+// back.
+package serverlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Names of files in the log directory.
+const (
+	// OutputFile contains the output of the server, with every line
+	// prefixed by the time it was written.
+	OutputFile = "server.log"
+
+	// ConsoleFile is the console log written by the server through
+	// --console-log. It contains chat messages and other console
+	// events.
+	ConsoleFile = "console.log"
+)
+
+// timeFormat is the format of the time prefixed to every line.
+const timeFormat = time.RFC3339Nano
+
+// Dir returns the directory logs are stored in.
+func Dir(dataPath string) string {
+	return filepath.Join(dataPath, "logs")
+}
+
+// Options control when logs are rotated and how many are kept.
+type Options struct {
+	// MaxSize is the size in megabytes at which a log is rotated.
+	MaxSize int
+
+	// MaxAge is how long to keep rotated logs for. If zero, they are
+	// kept regardless of age.
+	MaxAge time.Duration
+
+	// MaxBackups is how many rotated logs to keep. If zero, all are
+	// kept, subject to MaxAge.
+	MaxBackups int
+}
+
+// logger returns a rotating logger for the file at path.
+func (o Options) logger(path string) *lumberjack.Logger {
+	// Rotated logs are only removed by age in whole days.
+	maxAgeDays := 0
+	if o.MaxAge > 0 {
+		maxAgeDays = max(1, int((o.MaxAge+24*time.Hour-1)/(24*time.Hour)))
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    o.MaxSize,
+		MaxAge:     maxAgeDays,
+		MaxBackups: o.MaxBackups,
+		Compress:   true,
+	}
+}
+
+// Writer writes the output of the server to OutputFile, prefixing
+// every line with the time it was written. Writes must be complete
+// lines.
+type Writer struct {
+	mu sync.Mutex
+	l  *lumberjack.Logger
+}
+
+// Open returns a Writer for the log directory dir, creating it if
+// needed.
+func Open(dir string, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	return &Writer{l: opts.logger(filepath.Join(dir, OutputFile))}, nil
+}
+
+// WriteLine writes a single line of output.
+func (w *Writer) WriteLine(line string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.l.Write([]byte(time.Now().UTC().Format(timeFormat) + " " + line + "\n"))
+	return err
+}
+
+// Close closes the current log file.
+func (w *Writer) Close() error {
+	return w.l.Close()
+}
+
+// RotateConsoleLog rotates ConsoleFile in the log directory dir if it
+// isn't empty. The server appends to the console log without ever
+// rotating it, so this should be called before every start.
+func RotateConsoleLog(dir string, opts Options) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	path := filepath.Join(dir, ConsoleFile)
+	if inf, err := os.Stat(path); err != nil || inf.Size() == 0 {
+		return nil
+	}
+
+	l := opts.logger(path)
+	if err := l.Rotate(); err != nil {
+		return fmt.Errorf("failed to rotate console log: %w", err)
+	}
+	return l.Close()
+}
+
+// files returns the output logs in the log directory dir, oldest first.
+func files(dir string) ([]string, error) {
+	base := strings.TrimSuffix(OutputFile, filepath.Ext(OutputFile))
+	ext := filepath.Ext(OutputFile)
+
+	var rotated []string
+	for _, pattern := range []string{base + "-*" + ext, base + "-*" + ext + ".gz"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		rotated = append(rotated, matches...)
+	}
+
+	// Rotated logs are named after the time they were rotated, which
+	// sorts chronologically.
+	sort.Slice(rotated, func(i, j int) bool {
+		return strings.TrimSuffix(rotated[i], ".gz") < strings.TrimSuffix(rotated[j], ".gz")
+	})
+
+	current := filepath.Join(dir, OutputFile)
+	if _, err := os.Stat(current); err == nil {
+		rotated = append(rotated, current)
+	}
+	return rotated, nil
+}
+
+// Read writes every line of output in the log directory dir that was
+// written at or after since, and for which match returns true, to w. A
+// nil match matches every line.
+func Read(dir string, since time.Time, match func(line string) bool, w io.Writer) error {
+	paths, err := files(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		// Logs last modified before since can't contain matching lines.
+		if inf, err := os.Stat(path); err == nil && inf.ModTime().Before(since) {
+			continue
+		}
+
+		if err := readFile(path, since, match, w); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// readFile reads a single, optionally compressed, log file.
+func readFile(path string, since time.Time, match func(line string) bool, w io.Writer) error {
+	f, err := os.Open(path) //nolint:gosec // Why: By design.
+	if err != nil {
+		// Rotated logs may be compressed or removed while we read.
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close() //nolint:errcheck // Why: Best effort.
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gr.Close() //nolint:errcheck // Why: Best effort.
+		r = gr
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+
+		ts, _, _ := strings.Cut(line, " ")
+		if t, err := time.Parse(timeFormat, ts); err == nil && t.Before(since) {
+			continue
+		}
+		if match != nil && !match(line) {
+			continue
+		}
+
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
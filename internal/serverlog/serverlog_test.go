@@ -0,0 +1,95 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package serverlog_test
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/serverlog"
+	"gotest.tools/v3/assert"
+)
+
+// writeGzip writes a gzip compressed file containing s.
+func writeGzip(t *testing.T, path, s string) {
+	f, err := os.Create(path)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	_, err = gw.Write([]byte(s))
+	assert.NilError(t, err)
+	assert.NilError(t, gw.Close())
+}
+
+func TestReadIncludesRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339Nano)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+
+	writeGzip(t, filepath.Join(dir, "server-2020-01-01T00-00-00.000.log.gz"),
+		old+" old line\n"+recent+" rotated error\n")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "server-2020-01-02T00-00-00.000.log"),
+		[]byte(recent+" rotated info\n"), 0o600))
+
+	w, err := serverlog.Open(dir, serverlog.Options{MaxSize: 1})
+	assert.NilError(t, err)
+	assert.NilError(t, w.WriteLine("current error"))
+	assert.NilError(t, w.Close())
+
+	var buf strings.Builder
+	assert.NilError(t, serverlog.Read(dir, time.Time{}, nil, &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 4)
+	assert.Assert(t, strings.HasSuffix(lines[0], " old line"))
+	assert.Assert(t, strings.HasSuffix(lines[3], " current error"))
+
+	buf.Reset()
+	since := time.Now().Add(-time.Hour)
+	assert.NilError(t, serverlog.Read(dir, since, func(l string) bool { return strings.Contains(l, "error") }, &buf))
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 2, buf.String())
+	assert.Assert(t, strings.HasSuffix(lines[0], " rotated error"))
+	assert.Assert(t, strings.HasSuffix(lines[1], " current error"))
+}
+
+func TestRotateConsoleLog(t *testing.T) {
+	dir := t.TempDir()
+	opts := serverlog.Options{MaxSize: 1}
+
+	// Nothing to rotate.
+	assert.NilError(t, serverlog.RotateConsoleLog(dir, opts))
+	matches, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(matches), 0)
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, serverlog.ConsoleFile), []byte("[CHAT] hi\n"), 0o600))
+	assert.NilError(t, serverlog.RotateConsoleLog(dir, opts))
+
+	inf, err := os.Stat(filepath.Join(dir, serverlog.ConsoleFile))
+	assert.NilError(t, err)
+	assert.Equal(t, inf.Size(), int64(0))
+
+	matches, err = filepath.Glob(filepath.Join(dir, "console-*"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(matches), 1)
+}
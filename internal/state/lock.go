@@ -0,0 +1,86 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockSuffix is the suffix added to the state file's path to create the
+// path of its lock file.
+const lockSuffix = ".lock"
+
+// lockRetryInterval is how often to retry acquiring a lock held by
+// someone else.
+const lockRetryInterval = 500 * time.Millisecond
+
+// Lock is an advisory lock on a state file. It is used to prevent
+// multiple wrappers sharing the same install from modifying it at the
+// same time.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire takes an exclusive lock on the state file at path, waiting
+// until it is available or the context is canceled. The lock is held on
+// a separate file next to the state file so that the state file can be
+// atomically replaced while locked.
+func Acquire(ctx context.Context, path string) (*Lock, error) {
+	if path == "" {
+		path = "state.json"
+	}
+
+	//nolint:gosec // Why: By design.
+	f, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	t := time.NewTicker(lockRetryInterval)
+	defer t.Stop()
+
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close() //nolint:errcheck // Why: Best effort.
+			return nil, fmt.Errorf("failed to lock %s: %w", f.Name(), err)
+		}
+		if ok {
+			return &Lock{f: f}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close() //nolint:errcheck // Why: Best effort.
+			return nil, fmt.Errorf("failed to lock %s: %w", f.Name(), ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// Release releases the lock.
+func (l *Lock) Release() error {
+	if err := unlock(l.f); err != nil {
+		l.f.Close() //nolint:errcheck // Why: Best effort.
+		return err
+	}
+	return l.f.Close()
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+)
+
+// Manifests contain thousands of entries, so they're stored in their own
+// files next to the state file, and only rewritten when they change,
+// rather than on every save.
+const (
+	// manifestSuffix is the suffix added to the state file's path to
+	// create the path of the file containing State.Manifest.
+	manifestSuffix = ".manifest"
+
+	// previousManifestSuffix is the suffix added to the state file's path
+	// to create the path of the file containing State.PreviousManifest.
+	previousManifestSuffix = ".previous-manifest"
+)
+
+// legacyManifestKeys are the keys manifests were stored under in the
+// state file itself, before they were moved to their own files.
+var legacyManifestKeys = [...]string{"manifest", "previous_manifest"}
+
+// manifestFile is a manifest stored in its own file.
+type manifestFile struct {
+	// path is the path of the file.
+	path string
+
+	// m points to the manifest in the State.
+	m *factorio.Manifest
+
+	// saved is the manifest as last read or written, used to only write
+	// the file when the manifest changes.
+	saved factorio.Manifest
+}
+
+// manifestFiles returns the files the manifests of s are stored in.
+func (s *State) manifestFiles() [2]*manifestFile {
+	if s.manifests[0] == nil {
+		s.manifests = [2]*manifestFile{
+			{path: s.path + manifestSuffix, m: &s.Manifest},
+			{path: s.path + previousManifestSuffix, m: &s.PreviousManifest},
+		}
+	}
+	return s.manifests
+}
+
+// readManifests reads the manifests of s from their files. Manifests
+// stored in the state file by older versions of the wrapper, found in
+// raw, are used instead if present, and moved to their own files on the
+// next save.
+func (s *State) readManifests(raw map[string]json.RawMessage) error {
+	for i, f := range s.manifestFiles() {
+		if v, ok := raw[legacyManifestKeys[i]]; ok {
+			if err := json.Unmarshal(v, f.m); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", legacyManifestKeys[i], err)
+			}
+			continue
+		}
+
+		b, err := os.ReadFile(f.path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if err := json.Unmarshal(b, f.m); err != nil {
+			return fmt.Errorf("failed to decode manifest %s: %w", f.path, err)
+		}
+		f.saved = *f.m
+	}
+
+	return nil
+}
+
+// saveManifests writes every manifest of s that changed since it was
+// read or last written. Empty manifests are removed.
+func (s *State) saveManifests() error {
+	for _, f := range s.manifestFiles() {
+		if sameManifest(*f.m, f.saved) {
+			continue
+		}
+
+		if len(*f.m) == 0 {
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove manifest: %w", err)
+			}
+		} else if err := writeFile(f.path, *f.m); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+		f.saved = *f.m
+	}
+
+	return nil
+}
+
+// sameManifest returns true if a and b are the same manifest, i.e., the
+// same slice. Manifests are replaced rather than modified in place, so
+// this avoids comparing thousands of entries on every save.
+func sameManifest(a, b factorio.Manifest) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(a) == 0 || &a[0] == &b[0]
+}
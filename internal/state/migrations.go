@@ -0,0 +1,94 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentSchemaVersion is the version of the state file's schema
+// written by this version of the wrapper.
+const CurrentSchemaVersion = 2
+
+// migration upgrades a raw state file from one schema version to the
+// next one.
+type migration func(raw map[string]json.RawMessage) error
+
+// migrations contains every migration, keyed by the schema version it
+// upgrades from.
+var migrations = map[int]migration{
+	1: migrateV1ToV2,
+}
+
+// migrate upgrades the raw state file to CurrentSchemaVersion.
+func migrate(raw map[string]json.RawMessage) error {
+	// State files written before the schema was versioned are version 1.
+	schemaVersion := 1
+	if v, ok := raw["schema_version"]; ok {
+		if err := json.Unmarshal(v, &schemaVersion); err != nil {
+			return fmt.Errorf("failed to decode schema version: %w", err)
+		}
+	}
+
+	if schemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("state file uses schema version %d, but only up to %d is supported "+
+			"(was it written by a newer wrapper?)", schemaVersion, CurrentSchemaVersion)
+	}
+
+	for ; schemaVersion < CurrentSchemaVersion; schemaVersion++ {
+		m, ok := migrations[schemaVersion]
+		if !ok {
+			return fmt.Errorf("no migration from schema version %d", schemaVersion)
+		}
+
+		if err := m(raw); err != nil {
+			return fmt.Errorf("failed to migrate state from schema version %d: %w", schemaVersion, err)
+		}
+	}
+
+	b, err := json.Marshal(schemaVersion)
+	if err != nil {
+		return err
+	}
+	raw["schema_version"] = b
+
+	return nil
+}
+
+// migrateV1ToV2 seeds the install history with the installed version,
+// since version 1 did not track history.
+func migrateV1ToV2(raw map[string]json.RawMessage) error {
+	var version string
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return err
+		}
+	}
+	if version == "" {
+		return nil
+	}
+
+	b, err := json.Marshal([]Install{{Version: version}})
+	if err != nil {
+		return err
+	}
+	raw["history"] = b
+
+	return nil
+}
@@ -22,8 +22,16 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
 )
 
 // State tracks the state of a Factorio server as created by the
@@ -32,54 +40,261 @@ type State struct {
 	// path is the path where the state file is stored.
 	path string
 
+	// unknown contains fields in the state file that this version of the
+	// wrapper does not know about. They are preserved when saving so that
+	// running an older wrapper does not throw them away.
+	unknown map[string]json.RawMessage
+
+	// manifests are the files Manifest and PreviousManifest are stored
+	// in, created by manifestFiles.
+	manifests [2]*manifestFile
+
+	// SchemaVersion is the version of the schema used by the state file.
+	SchemaVersion int `json:"schema_version"`
+
 	// Version is the current version of Factorio installed.
 	Version string `json:"version"`
+
+	// Manifest contains every file that was installed for Version. It is
+	// used to detect corrupted or modified installs. It is stored in its
+	// own file next to the state file.
+	Manifest factorio.Manifest `json:"-"`
+
+	// History contains every version of Factorio installed by the
+	// wrapper, oldest first.
+	History []Install `json:"history,omitempty"`
+
+	// Previous is the version of Factorio that was installed before
+	// Version. It is kept until Version has started successfully, so
+	// that the upgrade can be rolled back.
+	Previous string `json:"previous,omitempty"`
+
+	// PreviousManifest is the manifest of Previous. Like Manifest, it is
+	// stored in its own file.
+	PreviousManifest factorio.Manifest `json:"-"`
+
+	// BadVersions contains every version of Factorio that failed to
+	// start after being upgraded to, and was rolled back.
+	BadVersions []string `json:"bad_versions,omitempty"`
+}
+
+// Install is a single install of Factorio done by the wrapper.
+type Install struct {
+	// Version is the version of Factorio that was installed.
+	Version string `json:"version"`
+
+	// InstalledAt is when the version was installed.
+	InstalledAt time.Time `json:"installed_at"`
+
+	// Channel is the version that was requested when this version was
+	// installed, e.g., 'stable' or '~2.0'.
+	Channel string `json:"channel,omitempty"`
+
+	// SHA256 is the SHA256 sum of the installed release.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// WrapperVersion is the version of the wrapper that installed it.
+	WrapperVersion string `json:"wrapper_version,omitempty"`
+
+	// Started is true if the server successfully started on this
+	// version.
+	Started bool `json:"started"`
+}
+
+// AddInstall records a new install of Factorio in the history.
+func (s *State) AddInstall(i Install) {
+	s.History = append(s.History, i)
+}
+
+// LastInstall returns the most recent install of the provided version,
+// or nil if it was never installed.
+func (s *State) LastInstall(version string) *Install {
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if s.History[i].Version == version {
+			return &s.History[i]
+		}
+	}
+
+	return nil
+}
+
+// MarkBad records that version failed to start.
+func (s *State) MarkBad(version string) {
+	if !s.IsBad(version) {
+		s.BadVersions = append(s.BadVersions, version)
+	}
+}
+
+// IsBad returns true if version failed to start.
+func (s *State) IsBad(version string) bool {
+	return slices.Contains(s.BadVersions, version)
 }
 
 // Open opens the state file and returns the state. If it doesn't exist,
 // it will return a new state. If path is empty, it will default to
-// "state.json".
-func Open(path string) *State {
+// "state.json". If the state file exists but can't be read, an error is
+// returned instead of a new state to prevent treating an existing
+// install as a fresh one.
+func Open(path string) (*State, error) {
 	if path == "" {
 		path = "state.json"
 	}
 
-	var defaultState = &State{path: path}
-
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return defaultState
-	}
-
 	//nolint:gosec // Why: By design.
 	f, err := os.Open(path)
 	if err != nil {
-		return defaultState
+		if errors.Is(err, os.ErrNotExist) {
+			return &State{path: path, SchemaVersion: CurrentSchemaVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to open state file: %w", err)
 	}
 	defer f.Close() //nolint:errcheck // Why: Best effort.
 
-	var s State
-	if err := json.NewDecoder(f).Decode(&s); err != nil {
-		return defaultState
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(f).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode state file %s: %w", path, err)
+	}
+
+	if err := migrate(raw); err != nil {
+		return nil, fmt.Errorf("failed to migrate state file %s: %w", path, err)
+	}
+
+	s, err := fromRaw(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode state file %s: %w", path, err)
 	}
 
 	// Ensure the path is set.
-	s.path = defaultState.path
+	s.path = path
+
+	if err := s.readManifests(raw); err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
 
-	return &s
+	return s, nil
 }
 
-// Save saves the state to the state file.
+// fromRaw creates a State from the fields of a state file, keeping track
+// of any fields that are not known.
+func fromRaw(raw map[string]json.RawMessage) (*State, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+
+	known := knownFields()
+	for k, v := range raw {
+		if _, ok := known[k]; ok || slices.Contains(legacyManifestKeys[:], k) {
+			continue
+		}
+
+		if s.unknown == nil {
+			s.unknown = make(map[string]json.RawMessage)
+		}
+		s.unknown[k] = v
+	}
+
+	return &s, nil
+}
+
+// knownFields returns the names of every field in the state file known
+// to this version of the wrapper.
+func knownFields() map[string]struct{} {
+	known := make(map[string]struct{})
+
+	t := reflect.TypeOf(State{})
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[name] = struct{}{}
+		}
+	}
+
+	return known
+}
+
+// toRaw returns the fields of the state file, including unknown fields.
+func (s *State) toRaw() (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	for k, v := range s.unknown {
+		if _, ok := raw[k]; !ok {
+			raw[k] = v
+		}
+	}
+
+	return raw, nil
+}
+
+// Save saves the state to the state file, along with any manifests that
+// changed. Files are written to a temporary file first and then renamed
+// over the original, so that a crash never leaves behind a partially
+// written file.
 func (s *State) Save() error {
 	if s.path == "" {
 		return fmt.Errorf("state path is unset")
 	}
+	s.SchemaVersion = CurrentSchemaVersion
 
-	//nolint:gosec // Why: By design.
-	f, err := os.Create(s.path)
+	// Manifests are written first, so that the state file never refers
+	// to an install whose manifest is missing.
+	if err := s.saveManifests(); err != nil {
+		return err
+	}
+
+	raw, err := s.toRaw()
 	if err != nil {
 		return err
 	}
-	defer f.Close() //nolint:errcheck // Why: Best effort.
+	return writeFile(s.path, raw)
+}
 
-	return json.NewEncoder(f).Encode(s)
+// writeFile atomically writes v, encoded as JSON, to path.
+func writeFile(path string, v any) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // Why: Best effort, fails once renamed.
+	defer f.Close()           //nolint:errcheck // Why: Best effort.
+
+	if err := json.NewEncoder(f).Encode(v); err != nil {
+		return err
+	}
+
+	// Ensure the contents are on disk before the rename makes them
+	// visible.
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// IsStateFile returns true if the provided file name is a file managed
+// by this package for the state file at path, e.g., the state file
+// itself, its lock or its manifests.
+func IsStateFile(path, name string) bool {
+	base := filepath.Base(path)
+	switch name {
+	case base, base + lockSuffix, base + manifestSuffix, base + previousManifestSuffix:
+		return true
+	}
+	return false
 }
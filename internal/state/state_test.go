@@ -0,0 +1,141 @@
+// Copyright (C) 2026 factorio-docker contributors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0
+
+package state_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/factorio-docker/internal/factorio"
+	"github.com/jaredallard/factorio-docker/internal/state"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanSaveAndOpenState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	st, err := state.Open(path)
+	assert.NilError(t, err)
+	assert.Equal(t, st.Version, "")
+
+	st.Version = "2.0.28"
+	assert.NilError(t, st.Save())
+
+	st, err = state.Open(path)
+	assert.NilError(t, err)
+	assert.Equal(t, st.Version, "2.0.28")
+
+	// Only the state file should be left behind.
+	files, err := os.ReadDir(filepath.Dir(path))
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+}
+
+func TestOpenSurfacesCorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NilError(t, os.WriteFile(path, []byte(`{"version": "2.0`), 0o600))
+
+	_, err := state.Open(path)
+	assert.ErrorContains(t, err, "failed to decode state file")
+}
+
+func TestStoresManifestsNextToState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	m := factorio.Manifest{{Path: "bin/x64/factorio", Size: 1, SHA256: "abc"}}
+
+	// Manifests used to be stored in the state file itself.
+	assert.NilError(t, os.WriteFile(path, []byte(`{"schema_version": 2, "version": "2.0.28", "manifest": [{"path": "bin/x64/factorio", "size": 1, "sha256": "abc"}]}`), 0o600))
+
+	st, err := state.Open(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, st.Manifest, m)
+	assert.NilError(t, st.Save())
+
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), "manifest"), string(b))
+
+	st, err = state.Open(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, st.Manifest, m)
+	assert.Equal(t, len(st.PreviousManifest), 0)
+
+	// Moving the manifest should move its file.
+	st.PreviousManifest, st.Manifest = st.Manifest, nil
+	assert.NilError(t, st.Save())
+
+	st, err = state.Open(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(st.Manifest), 0)
+	assert.DeepEqual(t, st.PreviousManifest, m)
+
+	files, err := os.ReadDir(filepath.Dir(path))
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 2)
+	for _, f := range files {
+		assert.Assert(t, state.IsStateFile(path, f.Name()), f.Name())
+	}
+}
+
+func TestAcquireWaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	lock, err := state.Acquire(context.Background(), path)
+	assert.NilError(t, err)
+
+	// A second lock should not be acquired while the first is held.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = state.Acquire(ctx, path)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.NilError(t, lock.Release())
+
+	lock, err = state.Acquire(context.Background(), path)
+	assert.NilError(t, err)
+	assert.NilError(t, lock.Release())
+}
+
+func TestMigratesAndPreservesUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NilError(t, os.WriteFile(path, []byte(`{"version":"1.1.110","from_the_future":true}`), 0o600))
+
+	st, err := state.Open(path)
+	assert.NilError(t, err)
+	assert.Equal(t, st.SchemaVersion, state.CurrentSchemaVersion)
+	assert.Equal(t, len(st.History), 1)
+	assert.Equal(t, st.History[0].Version, "1.1.110")
+
+	assert.NilError(t, st.Save())
+
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(b), `"from_the_future":true`), string(b))
+}
+
+func TestRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NilError(t, os.WriteFile(path, []byte(`{"schema_version":999}`), 0o600))
+
+	_, err := state.Open(path)
+	assert.ErrorContains(t, err, "schema version 999")
+}